@@ -1,9 +1,12 @@
 package internal
 
 import (
+	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type CommitType string
@@ -21,13 +24,64 @@ const (
 	TypeOther    CommitType = "other"
 )
 
+// commitTypePrefixes maps the prefixes of the type in a commit message to the commit type, e.g. `docs` is a `doc` prefix
+var commitTypePrefixes = []struct {
+	typ      CommitType
+	prefixes []string
+}{
+	{TypeFix, []string{"fix", "bug"}},
+	{TypeFeat, []string{"feat"}},
+	{TypeTest, []string{"test"}},
+	{TypeChore, []string{"chore", "update"}},
+	{TypeOps, []string{"ops", "ci", "cd", "build"}},
+	{TypeDocs, []string{"doc"}},
+	{TypePerf, []string{"perf"}},
+	{TypeRefactor, []string{"refactor", "rework"}},
+	{TypeSecurity, []string{"sec"}},
+}
+
+// commitTypeOf returns the commit type of the lower case type of a commit message
+func commitTypeOf(commitType string) CommitType {
+	for _, mapping := range commitTypePrefixes {
+		for _, prefix := range mapping.prefixes {
+			if strings.HasPrefix(commitType, prefix) {
+				return mapping.typ
+			}
+		}
+	}
+	return TypeOther
+}
+
 var commitRegexp = regexp.MustCompile(`#?\d*\s*\[?([a-zA-Z]*)\]?\s*([\(\[]([^\]\)]*)[\]\)])?\s*?(!?)(:?)\s*(.*)`)
 var specialChars = strings.NewReplacer("<", "&lt;", ">", "&gt;", "&", "&amp;")
+var unescapeSpecialChars = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// Footer is a commit trailer such as `Refs: #123` or `Co-authored-by: Jane <jane@example.com>`
+type Footer struct {
+	Token string
+	Value string
+}
+
+// ConventionalCommit is a parsed commit message
+type ConventionalCommit struct {
+	Type        CommitType
+	Scope       string
+	Description string
+	Breaking    bool
+	// BreakingChange is the description of the `BREAKING CHANGE:` footer
+	BreakingChange string
+	Footers        []Footer
+}
 
 func ParseCommitMessage(msg string) (CommitType, string, string, bool) {
+	commit := ParseCommit(msg)
+	return commit.Type, commit.Scope, commit.Description, commit.Breaking
+}
+
+// ParseCommit parses a commit message including its footers
+func ParseCommit(msg string) ConventionalCommit {
 	match := commitRegexp.FindStringSubmatch(msg)
 	var commitType, scope, description string
-	var typ CommitType
 	var major = false
 
 	if len(match) == 7 {
@@ -44,26 +98,8 @@ func ParseCommitMessage(msg string) (CommitType, string, string, bool) {
 		commitType = ""
 	}
 
-	if strings.HasPrefix(commitType, "fix") || strings.HasPrefix(commitType, "bug") {
-		typ = TypeFix
-	} else if strings.HasPrefix(commitType, "feat") {
-		typ = TypeFeat
-	} else if strings.HasPrefix(commitType, "test") {
-		typ = TypeTest
-	} else if strings.HasPrefix(commitType, "chore") || strings.HasPrefix(commitType, "update") {
-		typ = TypeChore
-	} else if strings.HasPrefix(commitType, "ops") || strings.HasPrefix(commitType, "ci") || strings.HasPrefix(commitType, "cd") || strings.HasPrefix(commitType, "build") {
-		typ = TypeOps
-	} else if strings.HasPrefix(commitType, "doc") {
-		typ = TypeDocs
-	} else if strings.HasPrefix(commitType, "perf") {
-		typ = TypePerf
-	} else if strings.HasPrefix(commitType, "refactor") || strings.HasPrefix(commitType, "rework") {
-		typ = TypeRefactor
-	} else if strings.HasPrefix(commitType, "sec") {
-		typ = TypeSecurity
-	} else {
-		typ = TypeOther
+	typ := commitTypeOf(commitType)
+	if typ == TypeOther {
 		scope = ""
 		description = msg
 	}
@@ -77,9 +113,21 @@ func ParseCommitMessage(msg string) (CommitType, string, string, bool) {
 			break
 		}
 	}
-	for _, line := range strings.Split(msg, "\n") {
-		if strings.HasPrefix(line, "BREAKING CHANGE:") {
+	footers := parseFooters(msg)
+	var breakingChanges []string
+	for _, footer := range footers {
+		if isBreakingChangeToken(footer.Token) {
+			breakingChanges = append(breakingChanges, footer.Value)
+		}
+	}
+	// breaking changes without a preceding blank line are not parsed as footers
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		if token, value, ok := strings.Cut(line, ":"); ok && isBreakingChangeToken(token) {
 			major = true
+			if len(breakingChanges) == 0 {
+				breakingChanges = append(breakingChanges, strings.TrimSpace(value+"\n"+strings.Join(lines[i+1:], "\n")))
+			}
 			break
 		}
 	}
@@ -87,12 +135,254 @@ func ParseCommitMessage(msg string) (CommitType, string, string, bool) {
 	scope = specialChars.Replace(scope)
 	commitDescription = specialChars.Replace(commitDescription)
 
-	return typ, scope, commitDescription, major
+	return ConventionalCommit{
+		Type:           typ,
+		Scope:          scope,
+		Description:    commitDescription,
+		Breaking:       major,
+		BreakingChange: specialChars.Replace(strings.Join(breakingChanges, "\n\n")),
+		Footers:        footers,
+	}
+}
+
+func isBreakingChangeToken(token string) bool {
+	return token == "BREAKING CHANGE" || token == "BREAKING-CHANGE"
+}
+
+var paragraphRegexp = regexp.MustCompile(`\n\s*\n`)
+var footerRegexp = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9-]*|BREAKING CHANGE)(: | #)(.*)$`)
+
+// parseFooters reads the footers of the trailing paragraphs, see footerStart.
+// Lines not starting with a token continue the value of the previous footer.
+func parseFooters(msg string) []Footer {
+	paragraphs := paragraphRegexp.Split(strings.TrimSpace(msg), -1)
+	start := footerStart(paragraphs)
+	var footers []Footer
+	for _, paragraph := range paragraphs[start:] {
+		lines := strings.Split(paragraph, "\n")
+		if len(footers) > 0 {
+			footers[len(footers)-1].Value += "\n"
+		}
+		for _, line := range lines {
+			line = strings.TrimRight(line, " \t\r")
+			if match := footerRegexp.FindStringSubmatch(line); match != nil {
+				value := match[3]
+				if match[2] == " #" {
+					value = "#" + value
+				}
+				footers = append(footers, Footer{Token: match[1], Value: value})
+				continue
+			}
+			footers[len(footers)-1].Value += "\n" + line
+		}
+	}
+	for i := range footers {
+		footers[i].Value = strings.TrimSpace(footers[i].Value)
+	}
+	return footers
+}
+
+// footerStart returns the index of the first footer paragraph, it is len(paragraphs) without footers.
+// The first footer paragraph begins with a footer token, the following ones contain footers.
+func footerStart(paragraphs []string) int {
+	start := len(paragraphs)
+	for i := len(paragraphs) - 1; i > 0; i-- {
+		lines := strings.Split(paragraphs[i], "\n")
+		if footerRegexp.MatchString(strings.TrimRight(lines[0], " \t\r")) {
+			start = i
+			continue
+		}
+		if !slices.ContainsFunc(lines, func(line string) bool { return footerRegexp.MatchString(strings.TrimRight(line, " \t\r")) }) {
+			break
+		}
+	}
+	return start
+}
+
+var issueTokens = map[string]bool{
+	"refs": true, "ref": true, "references": true, "see": true, "issue": true, "issues": true,
+	"closes": true, "close": true, "closed": true,
+	"fixes": true, "fix": true, "fixed": true,
+	"resolves": true, "resolve": true, "resolved": true,
 }
 
-var releaseCommitRegex = regexp.MustCompile(`^Release (v?)(\d+).(\d+).(\d+)( \(.*\))?$`)
+var issueRefRegexp = regexp.MustCompile(`^(#\d+|[A-Z][A-Z0-9_]+-\d+)$`)
+
+// IssueRefs returns the issues referenced by footers like `Refs: #123` or `Closes: PROJ-42`
+func (commit ConventionalCommit) IssueRefs() []string {
+	var refs []string
+	for _, footer := range commit.Footers {
+		if !issueTokens[strings.ToLower(footer.Token)] {
+			continue
+		}
+		for _, ref := range strings.FieldsFunc(footer.Value, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' }) {
+			if issueRefRegexp.MatchString(ref) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// Footer returns the values of all footers with the given token, the token is case-insensitive
+func (commit ConventionalCommit) Footer(token string) []string {
+	var values []string
+	for _, footer := range commit.Footers {
+		if strings.EqualFold(footer.Token, token) {
+			values = append(values, footer.Value)
+		}
+	}
+	return values
+}
+
+var squashItemRegexp = regexp.MustCompile(`^\s*[*-]\s+(.*)$`)
+var squashConventionalRegexp = regexp.MustCompile(`^[a-zA-Z]+(\([^)]*\))?!?:\s*\S`)
+
+// ParseSquashCommit parses the commits listed as `* feat: something` in the body of a squash commit,
+// it returns nil if the body does not list any conventional commit. The footers of the squash commit apply to all commits,
+// its breaking change is described at the commits marked as breaking or the first one if none is.
+// Commits starting with a gitmoji are parsed if gitmoji is set.
+func ParseSquashCommit(msg string, gitmoji bool) []ConventionalCommit {
+	paragraphs := paragraphRegexp.Split(strings.TrimSpace(msg), -1)
+	body := strings.Join(paragraphs[:footerStart(paragraphs)], "\n\n")
+	lines := strings.Split(body, "\n")
+	var items []string
+	for _, line := range lines[1:] {
+		if match := squashItemRegexp.FindStringSubmatch(line); match != nil {
+			items = append(items, match[1])
+			continue
+		}
+		if len(items) > 0 {
+			items[len(items)-1] += "\n" + line
+		}
+	}
+
+	var commits []ConventionalCommit
+	for _, item := range items {
+		if gitmoji {
+			if commit, ok := parseGitmoji(item); ok {
+				commits = append(commits, commit)
+				continue
+			}
+		}
+		if !squashConventionalRegexp.MatchString(item) {
+			continue
+		}
+		if commit := ParseCommit(item); commit.Type != TypeOther {
+			commits = append(commits, commit)
+		}
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+
+	footers := parseFooters(msg)
+	var breakingChanges []string
+	for _, footer := range footers {
+		if isBreakingChangeToken(footer.Token) {
+			breakingChanges = append(breakingChanges, specialChars.Replace(footer.Value))
+		}
+	}
+	breaking := slices.ContainsFunc(commits, func(commit ConventionalCommit) bool { return commit.Breaking })
+	for i := range commits {
+		commits[i].Footers = append(commits[i].Footers, footers...)
+		if len(breakingChanges) == 0 || (breaking && !commits[i].Breaking) || (!breaking && i > 0) {
+			continue
+		}
+		commits[i].Breaking = true
+		commits[i].BreakingChange = strings.TrimSpace(commits[i].BreakingChange + "\n\n" + strings.Join(breakingChanges, "\n\n"))
+	}
+	return commits
+}
+
+// mergeBody returns the body of a merge commit below the pull request title without Gitlab's `See merge request` line
+func mergeBody(msg string) string {
+	paragraphs := paragraphRegexp.Split(strings.TrimSpace(msg), -1)
+	var body []string
+	for i, paragraph := range paragraphs[1:] {
+		if i == 0 && !gitlabMergeRequestRegexp.MatchString(paragraph) {
+			// the first line is the title, see ParseMergeCommit
+			_, paragraph, _ = strings.Cut(paragraph, "\n")
+		}
+		if paragraph = strings.TrimSpace(gitlabMergeRequestRegexp.ReplaceAllString(paragraph, "")); paragraph != "" {
+			body = append(body, paragraph)
+		}
+	}
+	return strings.Join(body, "\n\n")
+}
+
+var githubMergeRegexp = regexp.MustCompile(`^Merge pull request #(\d+) from \S+`)
+var gitlabMergeRegexp = regexp.MustCompile(`^Merge branch '[^']+' into '[^']+'`)
+var gitlabMergeRequestRegexp = regexp.MustCompile(`(?m)^See merge request \S*!(\d+)\s*$`)
+
+// ParseMergeCommit reads the pull request title and reference from Github's `Merge pull request #12 from x/y`
+// and Gitlab's `Merge branch 'x' into 'y'` merge commits, the title is empty if the message does not contain it
+func ParseMergeCommit(msg string) (title, ref string, number int, ok bool) {
+	paragraphs := paragraphRegexp.Split(strings.TrimSpace(msg), -1)
+	if match := githubMergeRegexp.FindStringSubmatch(paragraphs[0]); match != nil {
+		number, _ = strconv.Atoi(match[1])
+		ref = "#" + match[1]
+	} else if gitlabMergeRegexp.MatchString(paragraphs[0]) {
+		if match := gitlabMergeRequestRegexp.FindStringSubmatch(msg); match != nil {
+			number, _ = strconv.Atoi(match[1])
+			ref = "!" + match[1]
+		}
+	} else {
+		return "", "", 0, false
+	}
+
+	if len(paragraphs) > 1 && !gitlabMergeRequestRegexp.MatchString(paragraphs[1]) {
+		title = strings.TrimSpace(strings.SplitN(paragraphs[1], "\n", 2)[0])
+	}
+	return title, ref, number, true
+}
+
+const (
+	// DefaultReleaseCommitTemplate is the message of release commits, {version} is replaced with the version like `v1.2.3`
+	DefaultReleaseCommitTemplate = "Release {version}"
+	// DefaultReleaseBranch is the branch the release commit is pushed to
+	DefaultReleaseBranch = "semanticore/release"
+)
+
+var releaseCommitRegexps sync.Map
+
+// CheckReleaseCommitTemplate validates that the template contains the {version} placeholder exactly once
+func CheckReleaseCommitTemplate(template string) error {
+	if strings.Count(template, "{version}") != 1 {
+		return fmt.Errorf("release commit template %q must contain {version} exactly once", template)
+	}
+	if strings.ContainsAny(template, "\r\n") {
+		return fmt.Errorf("release commit template %q must be a single line", template)
+	}
+	return nil
+}
+
+// ReleaseCommitMessage renders the release commit template, an empty template uses DefaultReleaseCommitTemplate
+func ReleaseCommitMessage(template, version string) string {
+	if template == "" {
+		template = DefaultReleaseCommitTemplate
+	}
+	return strings.ReplaceAll(template, "{version}", version)
+}
+
+// releaseCommitRegexp matches subjects of the template, optionally followed by a merge request reference like ` (#15)`
+func releaseCommitRegexp(template string) *regexp.Regexp {
+	if template == "" {
+		template = DefaultReleaseCommitTemplate
+	}
+	if cached, ok := releaseCommitRegexps.Load(template); ok {
+		return cached.(*regexp.Regexp)
+	}
+	before, after, _ := strings.Cut(template, "{version}")
+	r := regexp.MustCompile(`^` + regexp.QuoteMeta(before) + `(v?)(\d+)\.(\d+)\.(\d+)` + regexp.QuoteMeta(after) + `( \(.*\))?$`)
+	releaseCommitRegexps.Store(template, r)
+	return r
+}
 
-func DetectReleaseCommit(commit string, merge bool) (vPrefix string, major, minor, patch int) {
+// DetectReleaseCommit returns the version of a commit created with the release commit template, it is 0.0.0 for other commits.
+// The subject of merge commits may be on any line.
+func DetectReleaseCommit(commit string, merge bool, template string) (vPrefix string, major, minor, patch int) {
+	releaseCommitRegex := releaseCommitRegexp(template)
 	candidates := []string{strings.SplitN(commit, "\n\n", 2)[0]}
 	if merge {
 		candidates = strings.Split(commit, "\n")
@@ -1,6 +1,10 @@
 package internal
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestParseCommit(t *testing.T) {
 	var cases = []struct {
@@ -53,6 +57,8 @@ func TestParseCommit(t *testing.T) {
 		{`invalid(something): test`, TypeOther, ``, `invalid(something): test`, false},
 		// major commits
 		{"testing:\n\ttest\nBREAKING CHANGE: major commit", TypeTest, ``, `test`, true},
+		{"testing:\n\ttest\nBREAKING-CHANGE: major commit", TypeTest, ``, `test`, true},
+		{"testing:\n\ttest\nbreaking change: no major commit", TypeTest, ``, `test`, false},
 		{"testing!:\n\ttest\n", TypeTest, ``, `test`, true},
 		{"testing(scope)!:\n\ttest\n", TypeTest, `scope`, `test`, true},
 		// special chars
@@ -71,27 +77,162 @@ func TestDetectReleaseCommit(t *testing.T) {
 	var cases = []struct {
 		commit              string
 		merge               bool
+		template            string
 		vPrefix             string
 		major, minor, patch int
 	}{
-		{"Release v1.2.3", false, "v", 1, 2, 3},
-		{"Merge a into b\n\nRelease v1.2.3\n\nFoo bar", true, "v", 1, 2, 3},
-		{"multi line\n\nRelease v1.2.3\n\nFoo bar", false, "v", 0, 0, 0},
-		{"Release v1.2.3\nfoo", false, "v", 0, 0, 0},
-		{"Release v1.2.3\n\nfoo", false, "v", 1, 2, 3},
-		{"Fixed Release v1.2.3", false, "v", 0, 0, 0},
-		{"Release v1.2.3 was totally broken", false, "v", 0, 0, 0},
-		{"Release v1.2.3 (#15)", false, "v", 1, 2, 3},
-		{"Release v1.2.3 (#15)", true, "v", 1, 2, 3},
-		{"Release v1.2.3 (#15)\n\nCo-authored-by: test", false, "v", 1, 2, 3},
-		{"Release 1.2.3 (#15)\n\nCo-authored-by: test", false, "", 1, 2, 3},
-		{"Release 1.2.3 (#15)", true, "", 1, 2, 3},
-		{"Merge a into b\n\nRelease 1.2.3\n\nFoo bar", true, "", 1, 2, 3},
+		{"Release v1.2.3", false, "", "v", 1, 2, 3},
+		{"Merge a into b\n\nRelease v1.2.3\n\nFoo bar", true, "", "v", 1, 2, 3},
+		{"multi line\n\nRelease v1.2.3\n\nFoo bar", false, "", "v", 0, 0, 0},
+		{"Release v1.2.3\nfoo", false, "", "v", 0, 0, 0},
+		{"Release v1.2.3\n\nfoo", false, "", "v", 1, 2, 3},
+		{"Fixed Release v1.2.3", false, "", "v", 0, 0, 0},
+		{"Release v1.2.3 was totally broken", false, "", "v", 0, 0, 0},
+		{"Release v1.2.3 (#15)", false, "", "v", 1, 2, 3},
+		{"Release v1.2.3 (#15)", true, "", "v", 1, 2, 3},
+		{"Release v1.2.3 (#15)\n\nCo-authored-by: test", false, "", "v", 1, 2, 3},
+		{"Release 1.2.3 (#15)\n\nCo-authored-by: test", false, "", "", 1, 2, 3},
+		{"Release 1.2.3 (#15)", true, "", "", 1, 2, 3},
+		{"Merge a into b\n\nRelease 1.2.3\n\nFoo bar", true, "", "", 1, 2, 3},
+		{"chore(release): v1.2.3", false, "chore(release): {version}", "v", 1, 2, 3},
+		{"chore(release): v1.2.3 (#15)\n\nCo-authored-by: test", false, "chore(release): {version}", "v", 1, 2, 3},
+		{"Merge a into b\n\nchore(release): 1.2.3", true, "chore(release): {version}", "", 1, 2, 3},
+		{"Release v1.2.3", false, "chore(release): {version}", "v", 0, 0, 0},
+		{"chore(release): v1.2.3", false, "", "v", 0, 0, 0},
+		{"release [v1.2.3] done", false, "release [{version}] done", "v", 1, 2, 3},
+		{"release v1.2.3 done", false, "release [{version}] done", "v", 0, 0, 0},
 	}
 	for _, c := range cases {
-		vPrefix, major, minor, patch := DetectReleaseCommit(c.commit, c.merge)
+		vPrefix, major, minor, patch := DetectReleaseCommit(c.commit, c.merge, c.template)
 		if vPrefix != c.vPrefix || major != c.major || minor != c.minor || patch != c.patch {
 			t.Errorf("detectReleaseCommit %q failed with %q != %q, %d != %d, %d != %d, %d != %d", c.commit, c.vPrefix, vPrefix, c.major, major, c.minor, minor, c.patch, patch)
 		}
 	}
 }
+
+func TestReleaseCommitTemplate(t *testing.T) {
+	assert.Equal(t, "Release v1.2.3", ReleaseCommitMessage("", "v1.2.3"))
+	assert.Equal(t, "chore(release): 1.2.3", ReleaseCommitMessage("chore(release): {version}", "1.2.3"))
+	assert.NoError(t, CheckReleaseCommitTemplate("chore(release): {version}"))
+	assert.Error(t, CheckReleaseCommitTemplate("chore(release): new version"))
+	assert.Error(t, CheckReleaseCommitTemplate("{version} {version}"))
+	assert.Error(t, CheckReleaseCommitTemplate("Release {version}\n\nbody"))
+}
+
+func TestParseCommitFooters(t *testing.T) {
+	commit := ParseCommit("feat(api): export\n\nsome body\nNote that this is body text: yes\n\nRefs: #123, #124\nCloses: PROJ-42\nFixes #125\nCo-authored-by: Jane Doe <jane@example.com>\nAcked-by: someone\n  continued line")
+	assert.Equal(t, TypeFeat, commit.Type)
+	assert.Equal(t, "export", commit.Description)
+	assert.Equal(t, []Footer{
+		{"Refs", "#123, #124"},
+		{"Closes", "PROJ-42"},
+		{"Fixes", "#125"},
+		{"Co-authored-by", "Jane Doe <jane@example.com>"},
+		{"Acked-by", "someone\n  continued line"},
+	}, commit.Footers)
+	assert.Equal(t, []string{"#123", "#124", "PROJ-42", "#125"}, commit.IssueRefs())
+	assert.Equal(t, []string{"Jane Doe <jane@example.com>"}, commit.Footer("co-authored-by"))
+
+	commit = ParseCommit("fix: crash\n\nCloses: PROJ-42 and PROJ-43\nRefs #7\nSigned-off-by: #8")
+	assert.Equal(t, []string{"PROJ-42", "PROJ-43", "#7"}, commit.IssueRefs())
+
+	commit = ParseCommit("fix: crash\n\nsome body")
+	assert.Empty(t, commit.Footers)
+	assert.Empty(t, commit.IssueRefs())
+
+	// footers are only parsed at the end of the message
+	commit = ParseCommit("fix: crash\n\nRefs: #1\n\nsome body explaining the fix")
+	assert.Empty(t, commit.Footers)
+	commit = ParseCommit("fix: crash\n\nRefs: #1\n\nsome body\n\nCloses: #2")
+	assert.Equal(t, []Footer{{"Closes", "#2"}}, commit.Footers)
+}
+
+func TestParseCommitBreakingChange(t *testing.T) {
+	commit := ParseCommit("feat(api)!: drop v1\n\nBREAKING CHANGE: the v1 endpoints were removed.\nUse v2 instead.\n\nMigration guide: see docs\nRefs: #12")
+	assert.True(t, commit.Breaking)
+	assert.Equal(t, "the v1 endpoints were removed.\nUse v2 instead.\n\nMigration guide: see docs", commit.BreakingChange)
+	assert.Equal(t, []string{"#12"}, commit.IssueRefs())
+
+	commit = ParseCommit("feat: new config\n\nBREAKING-CHANGE: <config> moved")
+	assert.True(t, commit.Breaking)
+	assert.Equal(t, "&lt;config&gt; moved", commit.BreakingChange)
+
+	commit = ParseCommit("feat: new config\nBREAKING CHANGE: config moved\nto a new place")
+	assert.True(t, commit.Breaking)
+	assert.Equal(t, "config moved\nto a new place", commit.BreakingChange)
+
+	commit = ParseCommit("feat!: new config")
+	assert.True(t, commit.Breaking)
+	assert.Equal(t, "", commit.BreakingChange)
+}
+
+func TestParseSquashCommit(t *testing.T) {
+	commits := ParseSquashCommit("feat: csv export (#12)\n\n* feat(export): add csv writer\n\n* fix: escape quotes\n\nquotes were not escaped\n\n* wip\n\n* refactor(api)!: rename handler\n\nBREAKING CHANGE: handler moved\n\nCo-authored-by: Jane Doe <jane@example.com>", false)
+	assert.Len(t, commits, 3)
+	assert.Equal(t, TypeFeat, commits[0].Type)
+	assert.Equal(t, "export", commits[0].Scope)
+	assert.Equal(t, "add csv writer", commits[0].Description)
+	assert.Equal(t, TypeFix, commits[1].Type)
+	assert.Equal(t, "escape quotes", commits[1].Description)
+	assert.Equal(t, TypeRefactor, commits[2].Type)
+	assert.True(t, commits[2].Breaking)
+	assert.Equal(t, "handler moved", commits[2].BreakingChange)
+	assert.False(t, commits[0].Breaking)
+	assert.False(t, commits[1].Breaking)
+	for _, commit := range commits {
+		assert.Equal(t, []string{"Jane Doe <jane@example.com>"}, commit.Footer("Co-authored-by"))
+	}
+
+	// the breaking change of the squash commit is described once if no commit is marked as breaking
+	commits = ParseSquashCommit("feat: new api (#13)\n\n* feat: add v2\n* fix: typo\n\nBREAKING CHANGE: v1 is gone\nRefs: #7", false)
+	assert.Len(t, commits, 2)
+	assert.True(t, commits[0].Breaking)
+	assert.Equal(t, "v1 is gone", commits[0].BreakingChange)
+	assert.Equal(t, "typo", commits[1].Description)
+	assert.False(t, commits[1].Breaking)
+	assert.Equal(t, []string{"#7"}, commits[1].IssueRefs())
+
+	commits = ParseSquashCommit("✨ csv export (#12)\n\n* ✨ add csv writer\n* 🐛 escape quotes\n* fix: conventional fix", true)
+	assert.Len(t, commits, 3)
+	assert.Equal(t, TypeFeat, commits[0].Type)
+	assert.Equal(t, "add csv writer", commits[0].Description)
+	assert.Equal(t, TypeFix, commits[1].Type)
+	assert.Equal(t, TypeFix, commits[2].Type)
+	assert.Nil(t, ParseSquashCommit("✨ csv export (#12)\n\n* ✨ add csv writer", false))
+
+	assert.Nil(t, ParseSquashCommit("feat: csv export (#12)\n\n- fix typo in readme\n- wip", false))
+	assert.Nil(t, ParseSquashCommit("feat: csv export", false))
+}
+
+func TestMergeBody(t *testing.T) {
+	assert.Equal(t, "", mergeBody("Merge pull request #12 from x/y"))
+	assert.Equal(t, "", mergeBody("Merge pull request #12 from x/y\n\nfeat: shiny feature"))
+	assert.Equal(t, "more text\n\nRefs: #3", mergeBody("Merge pull request #12 from x/y\n\nfeat: shiny feature\nmore text\n\nRefs: #3"))
+	assert.Equal(t, "BREAKING CHANGE: moved", mergeBody("Merge branch 'feature' into 'main'\n\nfix: gitlab fix\n\nBREAKING CHANGE: moved\n\nSee merge request group/repo!7"))
+	assert.Equal(t, "", mergeBody("Merge branch 'feature' into 'main'\n\nSee merge request group/repo!7"))
+}
+
+func TestParseMergeCommit(t *testing.T) {
+	var cases = []struct {
+		commit string
+		title  string
+		ref    string
+		number int
+		ok     bool
+	}{
+		{"Merge pull request #12 from x/y\n\nfeat: shiny feature", "feat: shiny feature", "#12", 12, true},
+		{"Merge pull request #12 from x/y\n\nfeat: shiny feature\nmore text", "feat: shiny feature", "#12", 12, true},
+		{"Merge pull request #12 from x/y", "", "#12", 12, true},
+		{"Merge branch 'feature' into 'main'\n\nfix: gitlab fix\n\nSee merge request group/repo!7", "fix: gitlab fix", "!7", 7, true},
+		{"Merge branch 'feature' into 'main'\n\nSee merge request group/repo!7", "", "!7", 7, true},
+		{"Merge branch 'feature' into 'main'", "", "", 0, true},
+		{"Merge branch 'local'", "", "", 0, false},
+		{"feat: no merge", "", "", 0, false},
+	}
+	for _, c := range cases {
+		title, ref, number, ok := ParseMergeCommit(c.commit)
+		if title != c.title || ref != c.ref || number != c.number || ok != c.ok {
+			t.Errorf("ParseMergeCommit %q failed with %q != %q, %q != %q, %d != %d, %v != %v", c.commit, c.title, title, c.ref, ref, c.number, number, c.ok, ok)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Contributor is an author or co-author of the commits of a release
+type Contributor struct {
+	Name  string
+	Email string
+	// Handle is the username at the forge, it is empty if unknown
+	Handle string
+}
+
+var errNoUserFound = errors.New("no user found")
+
+var mailmapAddressRegexp = regexp.MustCompile(`\s*([^<]*?)\s*<([^>]*)>`)
+var coAuthorRegexp = regexp.MustCompile(`^(.*?)\s*<([^>]+)>$`)
+var githubNoreplyRegexp = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+
+type mailmapEntry struct {
+	properName, properEmail string
+	commitName, commitEmail string
+}
+
+// mailmap maps author names and emails to their canonical form, see gitmailmap(5)
+type mailmap []mailmapEntry
+
+func parseMailmap(content string) mailmap {
+	var entries mailmap
+	for _, line := range strings.Split(content, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		matches := mailmapAddressRegexp.FindAllStringSubmatch(line, 2)
+		switch len(matches) {
+		case 1:
+			// `Proper Name <commit@email>`
+			entries = append(entries, mailmapEntry{properName: matches[0][1], commitEmail: matches[0][2]})
+		case 2:
+			// `[Proper Name] <proper@email> [Commit Name] <commit@email>`
+			entries = append(entries, mailmapEntry{
+				properName:  matches[0][1],
+				properEmail: matches[0][2],
+				commitName:  matches[1][1],
+				commitEmail: matches[1][2],
+			})
+		}
+	}
+	return entries
+}
+
+// resolve returns the canonical name and email, entries matching name and email take precedence over email-only entries
+func (m mailmap) resolve(name, email string) (string, string) {
+	var match *mailmapEntry
+	for i, entry := range m {
+		if !strings.EqualFold(entry.commitEmail, email) {
+			continue
+		}
+		if entry.commitName == name {
+			match = &m[i]
+			break
+		}
+		if entry.commitName == "" && match == nil {
+			match = &m[i]
+		}
+	}
+	if match == nil {
+		return name, email
+	}
+	if match.properName != "" {
+		name = match.properName
+	}
+	if match.properEmail != "" {
+		email = match.properEmail
+	}
+	return name, email
+}
+
+// readMailmap reads the `.mailmap` of the given commit, a missing file results in an empty mailmap
+func readMailmap(commit *object.Commit) mailmap {
+	file, err := commit.File(".mailmap")
+	if err != nil {
+		return nil
+	}
+	content, err := file.Contents()
+	if err != nil {
+		log.Printf("[semanticore] unable to read .mailmap: %s", err)
+		return nil
+	}
+	return parseMailmap(content)
+}
+
+// isBot detects bot accounts like `dependabot[bot]` and the authors listed in exclude
+func isBot(name, email string, exclude []string) bool {
+	if strings.HasSuffix(name, "[bot]") || strings.Contains(email, "[bot]") {
+		return true
+	}
+	for _, excluded := range exclude {
+		if excluded != "" && (strings.EqualFold(excluded, name) || strings.EqualFold(excluded, email)) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectContributors returns the authors and co-authors of the commits sorted by name,
+// contributors are deduplicated by their email after applying the mailmap
+func collectContributors(commits []*object.Commit, mm mailmap, exclude []string) []Contributor {
+	seen := make(map[string]bool)
+	var contributors []Contributor
+	add := func(name, email string) {
+		name, email = mm.resolve(strings.TrimSpace(name), strings.TrimSpace(email))
+		key := strings.ToLower(email)
+		if key == "" {
+			key = name
+		}
+		if seen[key] || isBot(name, email, exclude) {
+			return
+		}
+		seen[key] = true
+		contributors = append(contributors, Contributor{Name: name, Email: email})
+	}
+
+	for _, commit := range commits {
+		add(commit.Author.Name, commit.Author.Email)
+		for _, footer := range parseFooters(commit.Message) {
+			if !strings.EqualFold(footer.Token, "Co-authored-by") {
+				continue
+			}
+			if match := coAuthorRegexp.FindStringSubmatch(footer.Value); match != nil {
+				add(match[1], match[2])
+			}
+		}
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return strings.ToLower(contributors[i].Name) < strings.ToLower(contributors[j].Name)
+	})
+	return contributors
+}
+
+// resolveHandles looks up the forge usernames of the contributors, Github noreply emails are resolved without the backend
+func resolveHandles(contributors []Contributor, backend Backend) {
+	for i, contributor := range contributors {
+		if match := githubNoreplyRegexp.FindStringSubmatch(contributor.Email); match != nil {
+			contributors[i].Handle = match[1]
+			continue
+		}
+		if backend == nil {
+			continue
+		}
+		handle, err := backend.FindUser(contributor.Email)
+		if err != nil {
+			if !errors.Is(err, errNoUserFound) {
+				log.Printf("[semanticore] unable to look up user %s: %s", contributor.Email, err)
+			}
+			continue
+		}
+		contributors[i].Handle = handle
+	}
+}
+
+// formatContributors renders the contributors section of the changelog
+func formatContributors(contributors []Contributor) string {
+	if len(contributors) == 0 {
+		return ""
+	}
+	changelog := fmt.Sprintln("### Contributors")
+	changelog += fmt.Sprintln()
+	for _, contributor := range contributors {
+		if contributor.Handle != "" {
+			changelog += fmt.Sprintf("- %s (@%s)\n", contributor.Name, contributor.Handle)
+		} else {
+			changelog += fmt.Sprintf("- %s\n", contributor.Name)
+		}
+	}
+	return changelog + fmt.Sprintln()
+}
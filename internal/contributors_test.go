@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailmap(t *testing.T) {
+	mm := parseMailmap(`# comment
+Jane Doe <jane@example.com>
+<jane@example.com> <jane@old.example.com>
+Jane Doe <jane@example.com> jd <jane@work.example.com> # only for jd
+Bob <bob@example.com> <BOB@example.com>
+`)
+
+	name, email := mm.resolve("jane", "jane@example.com")
+	assert.Equal(t, "Jane Doe", name)
+	assert.Equal(t, "jane@example.com", email)
+
+	name, email = mm.resolve("Jane", "jane@old.example.com")
+	assert.Equal(t, "Jane", name)
+	assert.Equal(t, "jane@example.com", email)
+
+	name, email = mm.resolve("jd", "jane@work.example.com")
+	assert.Equal(t, "Jane Doe", name)
+	assert.Equal(t, "jane@example.com", email)
+
+	name, email = mm.resolve("someone", "jane@work.example.com")
+	assert.Equal(t, "someone", name)
+	assert.Equal(t, "jane@work.example.com", email)
+
+	name, email = mm.resolve("bob", "bob@EXAMPLE.com")
+	assert.Equal(t, "Bob", name)
+	assert.Equal(t, "bob@example.com", email)
+}
+
+func TestReadRepositoryContributors(t *testing.T) {
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+	testCommit("chore: initial commit")
+
+	mailmap, err := mockWt.Filesystem.Create(".mailmap")
+	assert.NoError(t, err)
+	mailmap.Write([]byte("Jane Doe <jane@example.com> <jane@old.example.com>\n"))
+	assert.NoError(t, mailmap.Close())
+	_, err = mockWt.Add(".mailmap")
+	assert.NoError(t, err)
+
+	file, err := mockWt.Filesystem.Create("test.file")
+	assert.NoError(t, err)
+	authorCommit := func(msg, name, email string) {
+		file.Write([]byte(msg))
+		mockWt.Add("test.file")
+		_, err := mockWt.Commit(msg, &git.CommitOptions{Author: &object.Signature{Name: name, Email: email, When: time.Now()}})
+		assert.NoError(t, err)
+	}
+
+	authorCommit("feat: export", "jane", "jane@old.example.com")
+	authorCommit("fix: crash\n\nCo-authored-by: Bob <bob@example.com>\nCo-authored-by: Jane Doe <jane@example.com>", "Jane Doe", "jane@example.com")
+	authorCommit("chore: update dependencies", "dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com")
+	authorCommit("docs: readme", "Octo Cat", "583231+octocat@users.noreply.github.com")
+	authorCommit("ci: pipeline", "Semanticore Bot", "semanticore@aoe.com")
+	authorCommit("fix: typo [skip changelog]", "Skipped", "skipped@example.com")
+
+	repository, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Empty(t, repository.Contributors)
+	assert.NotContains(t, repository.Changelog(), "### Contributors")
+
+	backend := &testBackend{users: map[string]string{"bob@example.com": "bobby"}}
+	repository, err = ReadRepository(mockRepo, Options{
+		Contributors:        true,
+		ExcludeContributors: []string{"semanticore@aoe.com"},
+		ContributorHandles:  true,
+		Backend:             backend,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Contributor{
+		{Name: "Bob", Email: "bob@example.com", Handle: "bobby"},
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Name: "Octo Cat", Email: "583231+octocat@users.noreply.github.com", Handle: "octocat"},
+		{Name: "testing", Email: "testing@example.com"},
+	}, repository.Contributors)
+	assert.Contains(t, repository.Changelog(), "### Contributors\n\n- Bob (@bobby)\n- Jane Doe\n- Octo Cat (@octocat)\n- testing\n\n")
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// GitCredentials returns the credentials for remoteURL configured in git's credential helpers.
+// It returns nil for SSH remotes, which use the SSH agent, and if no credentials are found.
+func GitCredentials(remoteURL string) transport.AuthMethod {
+	u, err := url.Parse(remoteURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	if u.User != nil {
+		// credentials in the URL are used by go-git
+		return nil
+	}
+
+	input := fmt.Sprintf("protocol=%s\nhost=%s\npath=%s\n\n", u.Scheme, u.Host, strings.TrimPrefix(u.Path, "/"))
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		log.Printf("[semanticore] no git credentials found for %s: %s %s", u.Host, err, strings.TrimSpace(stderr.String()))
+		return nil
+	}
+	return parseCredentials(string(output))
+}
+
+// parseCredentials reads the `key=value` lines of `git credential fill`
+func parseCredentials(output string) transport.AuthMethod {
+	auth := &githttp.BasicAuth{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		switch key {
+		case "username":
+			auth.Username = value
+		case "password":
+			auth.Password = value
+		}
+	}
+	if auth.Password == "" {
+		return nil
+	}
+	return auth
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+const commitMsgHookMarker = "# installed by semanticore"
+
+var ErrHookExists = errors.New("commit-msg hook exists and was not installed by semanticore")
+
+// InstallCommitMsgHook writes a commit-msg hook which lints the message with `<command> <args> lint -message-file`.
+// Existing hooks not installed by semanticore are only replaced if force is set.
+func InstallCommitMsgHook(repo *git.Repository, command string, args []string, force bool) (string, error) {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", fmt.Errorf("unable to install hook in a repository without filesystem storage")
+	}
+	hooksDir := filepath.Join(storage.Filesystem().Root(), "hooks")
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", fmt.Errorf("unable to read repository config: %w", err)
+	}
+	if hooksPath := cfg.Raw.Section("core").Option("hooksPath"); hooksPath != "" {
+		hooksDir = hooksPath
+		if !filepath.IsAbs(hooksDir) {
+			wt, err := repo.Worktree()
+			if err != nil {
+				return "", fmt.Errorf("unable to resolve core.hooksPath %s: %w", hooksPath, err)
+			}
+			hooksDir = filepath.Join(wt.Filesystem.Root(), hooksPath)
+		}
+	}
+
+	hookPath := filepath.Join(hooksDir, "commit-msg")
+	if existing, err := os.ReadFile(hookPath); err == nil && !force && !strings.Contains(string(existing), commitMsgHookMarker) {
+		return "", fmt.Errorf("%w: %s", ErrHookExists, hookPath)
+	}
+
+	quoted := []string{shellQuote(command)}
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	hook := fmt.Sprintf("#!/bin/sh\n%s, https://github.com/aoepeople/semanticore\nexec %s lint -message-file \"$1\"\n", commitMsgHookMarker, strings.Join(quoted, " "))
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", fmt.Errorf("unable to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(hookPath, []byte(hook), 0755); err != nil {
+		return "", fmt.Errorf("unable to write hook: %w", err)
+	}
+	return hookPath, nil
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=.,/:") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ReadCommitMessageFile reads a commit message file as passed to the commit-msg hook, comments and everything after
+// the scissors line of `git commit --verbose` are removed
+func ReadCommitMessageFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read commit message: %w", err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallCommitMsgHook(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	assert.NoError(t, err)
+
+	path, err := InstallCommitMsgHook(repo, "semanticore", []string{"-scopes=api,ui", "-issue-url=https://example.com/{id} x's"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".git", "hooks", "commit-msg"), path)
+	hook, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(hook), `exec semanticore -scopes=api,ui '-issue-url=https://example.com/{id} x'\''s' lint -message-file "$1"`)
+	stat, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), stat.Mode().Perm())
+
+	// reinstalling replaces our own hook
+	_, err = InstallCommitMsgHook(repo, "semanticore", nil, false)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), 0755))
+	_, err = InstallCommitMsgHook(repo, "semanticore", nil, false)
+	assert.ErrorIs(t, err, ErrHookExists)
+	_, err = InstallCommitMsgHook(repo, "semanticore", nil, true)
+	assert.NoError(t, err)
+
+	cfg, err := repo.Config()
+	assert.NoError(t, err)
+	cfg.Raw.Section("core").SetOption("hooksPath", ".githooks")
+	assert.NoError(t, repo.SetConfig(cfg))
+	path, err = InstallCommitMsgHook(repo, "semanticore", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".githooks", "commit-msg"), path)
+}
+
+func TestReadCommitMessageFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	assert.NoError(t, os.WriteFile(path, []byte("feat: something\n\nbody\n# Please enter the commit message\n# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n"), 0644))
+
+	msg, err := ReadCommitMessageFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "feat: something\n\nbody", msg)
+
+	_, err = ReadCommitMessageFile(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -8,21 +8,25 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 )
 
 type Github struct {
 	server string
 	token  string
 	repo   string
+	// releaseBranch is the source branch of the release merge request
+	releaseBranch string
 }
 
 var _ Backend = Github{}
 
-func NewGithubBackend(token, repo string) Github {
+func NewGithubBackend(token, repo, releaseBranch string) Github {
 	return Github{
-		server: "https://api.github.com",
-		token:  token,
-		repo:   repo,
+		server:        "https://api.github.com",
+		token:         token,
+		repo:          repo,
+		releaseBranch: releaseBranch,
 	}
 }
 
@@ -49,6 +53,9 @@ func (github Github) request(method, endpoint string, expectedStatus int, body i
 	defer resp.Body.Close()
 	if resp.StatusCode != expectedStatus {
 		b, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: expected status is %d: %v %s", errNotFound, expectedStatus, resp, string(b))
+		}
 		return fmt.Errorf("expected status is %d: %v %s", expectedStatus, resp, string(b))
 	}
 	if target == nil {
@@ -75,7 +82,7 @@ func (github Github) findOpenMergeRequest() (int, error) {
 	}
 
 	for _, mr := range mrs {
-		if mr.Head.Ref == "semanticore/release" && mr.State == "open" {
+		if mr.Head.Ref == github.releaseBranch && mr.State == "open" {
 			log.Printf("[Github] merge request found: %d", mr.IID)
 			return mr.IID, nil
 		}
@@ -92,6 +99,14 @@ type githubPullBody struct {
 	Head  string `json:"head,omitempty"`
 }
 
+func (github Github) ReleaseMergeRequest() (int, error) {
+	iid, err := github.findOpenMergeRequest()
+	if errors.Is(err, errNoMergeRequestFound) {
+		return 0, nil
+	}
+	return iid, err
+}
+
 func (github Github) CloseMergeRequest() error {
 	iid, err := github.findOpenMergeRequest()
 	if errors.Is(err, errNoMergeRequestFound) {
@@ -121,13 +136,13 @@ func (github Github) MergeRequest(target, title, description, labels string) err
 	if iid > 0 {
 		return github.request(http.MethodPatch, fmt.Sprintf("/pulls/%d", iid), http.StatusOK, data, nil)
 	}
-	data.Head = "semanticore/release"
+	data.Head = github.releaseBranch
 	return github.request(http.MethodPost, "/pulls", http.StatusCreated, data, nil)
 }
 
 type githubReleaseBody struct {
 	TagName              string `json:"tag_name"`
-	TargetCommitish      string `json:"target_commitish"`
+	TargetCommitish      string `json:"target_commitish,omitempty"`
 	Name                 string `json:"name"`
 	GenerateReleaseNotes bool   `json:"generate_release_notes"`
 	Body                 string `json:"body"`
@@ -144,6 +159,17 @@ func (github Github) Release(tag, ref, changelog string) error {
 	return github.request(http.MethodPost, "/releases", http.StatusCreated, data, nil)
 }
 
+func (github Github) HasRelease(tag string) (bool, error) {
+	err := github.request(http.MethodGet, "/releases/tags/"+url.PathEscape(tag), http.StatusOK, nil, nil)
+	if errors.Is(err, errNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to get release %s: %w", tag, err)
+	}
+	return true, nil
+}
+
 func (github Github) MainBranch() (string, error) {
 	var repo struct {
 		DefaultBranch string `json:"default_branch"`
@@ -156,6 +182,94 @@ func (github Github) MainBranch() (string, error) {
 	return repo.DefaultBranch, nil
 }
 
+type githubPull struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+func (pull githubPull) info() MergeRequestInfo {
+	return MergeRequestInfo{
+		Number: pull.Number,
+		Ref:    fmt.Sprintf("#%d", pull.Number),
+		Title:  pull.Title,
+		URL:    pull.HTMLURL,
+		Author: pull.User.Login,
+	}
+}
+
+func (github Github) FindMergeRequest(number int) (MergeRequestInfo, error) {
+	var pull githubPull
+	if err := github.request(http.MethodGet, fmt.Sprintf("/pulls/%d", number), http.StatusOK, nil, &pull); err != nil {
+		return MergeRequestInfo{}, fmt.Errorf("unable to get pull request %d: %w", number, err)
+	}
+	return pull.info(), nil
+}
+
+func (github Github) FindCommitMergeRequest(sha string) (MergeRequestInfo, error) {
+	var pulls []githubPull
+	if err := github.request(http.MethodGet, fmt.Sprintf("/commits/%s/pulls", sha), http.StatusOK, nil, &pulls); err != nil {
+		return MergeRequestInfo{}, fmt.Errorf("unable to get pull requests of commit %s: %w", sha, err)
+	}
+	if len(pulls) == 0 {
+		return MergeRequestInfo{}, errNoMergeRequestFound
+	}
+	return pulls[0].info(), nil
+}
+
+func (github Github) CommentMergeRequest(number int, body string) error {
+	data := struct {
+		Body string `json:"body"`
+	}{
+		Body: body,
+	}
+	return github.request(http.MethodPost, fmt.Sprintf("/issues/%d/comments", number), http.StatusCreated, data, nil)
+}
+
+func (github Github) MergeRequestComments(number int) ([]string, error) {
+	var bodies []string
+	for page := 1; ; page++ {
+		var comments []struct {
+			Body string `json:"body"`
+		}
+		if err := github.request(http.MethodGet, fmt.Sprintf("/issues/%d/comments?per_page=%d&page=%d", number, commentsPerPage, page), http.StatusOK, nil, &comments); err != nil {
+			return nil, fmt.Errorf("unable to get comments of pull request %d: %w", number, err)
+		}
+		for _, comment := range comments {
+			bodies = append(bodies, comment.Body)
+		}
+		if len(comments) < commentsPerPage {
+			return bodies, nil
+		}
+	}
+}
+
+func (github Github) FindUser(email string) (string, error) {
+	var commits []struct {
+		Author *struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	}
+	if err := github.request(http.MethodGet, "/commits?per_page=1&author="+url.QueryEscape(email), http.StatusOK, nil, &commits); err != nil {
+		return "", fmt.Errorf("unable to get commits of %s: %w", email, err)
+	}
+	if len(commits) == 0 || commits[0].Author == nil {
+		return "", errNoUserFound
+	}
+	return commits[0].Author.Login, nil
+}
+
+func (github Github) Links() Links {
+	return Links{
+		Issue:   "https://github.com/" + github.repo + "/issues/{id}",
+		Commit:  "https://github.com/" + github.repo + "/commit/{hash}",
+		Compare: "https://github.com/" + github.repo + "/compare/{from}...{to}",
+	}
+}
+
 func (github Github) SetAuth(r *http.Request) {
 	r.SetBasicAuth("Github-ci-token", github.token)
 }
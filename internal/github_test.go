@@ -1,9 +1,11 @@
 package internal
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +16,7 @@ func TestGithub(t *testing.T) {
 	testserver := httptest.NewServer(testmux)
 	defer testserver.Close()
 
-	github := NewGithubBackend("test-token", "my/testrepo")
+	github := NewGithubBackend("test-token", "my/testrepo", DefaultReleaseBranch)
 
 	github.server = testserver.URL
 	assert.Error(t, github.request(http.MethodGet, "notfound", http.StatusAccepted, nil, nil))
@@ -63,6 +65,17 @@ func TestGithub(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 3, num)
 
+	otherBranch := NewGithubBackend("test-token", "my/testrepo", "chore/release")
+	otherBranch.server = testserver.URL
+	_, err = otherBranch.findOpenMergeRequest()
+	assert.ErrorIs(t, err, errNoMergeRequestFound)
+	num, err = otherBranch.ReleaseMergeRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, num)
+	num, err = github.ReleaseMergeRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, num)
+
 	testmux.HandleFunc("/repos/my/testrepo/pulls/3", func(w http.ResponseWriter, r *http.Request) {})
 	assert.NoError(t, github.CloseMergeRequest())
 
@@ -70,10 +83,26 @@ func TestGithub(t *testing.T) {
 	noMrs = true
 	assert.NoError(t, github.MergeRequest("main", "Release v1.2.3", "release description", "tag1,tag2"))
 
+	var release map[string]interface{}
 	testmux.HandleFunc("/repos/my/testrepo/releases", func(w http.ResponseWriter, r *http.Request) {
+		release = nil
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&release))
 		w.WriteHeader(http.StatusCreated)
 	})
 	assert.NoError(t, github.Release("main", "v1.2.3", "changelog"))
+	assert.Equal(t, "v1.2.3", release["target_commitish"])
+	assert.NoError(t, github.Release("v1.2.3", "", "changelog"))
+	assert.NotContains(t, release, "target_commitish")
+
+	testmux.HandleFunc("/repos/my/testrepo/releases/tags/v1.2.3", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "v1.2.3"}`)
+	})
+	exists, err := github.HasRelease("v1.2.3")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = github.HasRelease("v1.2.4")
+	assert.NoError(t, err)
+	assert.False(t, exists)
 
 	testmux.HandleFunc("/repos/my/testrepo", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, `{"default_branch": "main"}`)
@@ -81,4 +110,72 @@ func TestGithub(t *testing.T) {
 	branch, err := github.MainBranch()
 	assert.NoError(t, err)
 	assert.Equal(t, "main", branch)
+
+	assert.Equal(t, Links{
+		Issue:   "https://github.com/my/testrepo/issues/{id}",
+		Commit:  "https://github.com/my/testrepo/commit/{hash}",
+		Compare: "https://github.com/my/testrepo/compare/{from}...{to}",
+	}, github.Links())
+
+	testmux.HandleFunc("/repos/my/testrepo/pulls/12", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number": 12, "title": "feat: something", "html_url": "https://github.com/my/testrepo/pull/12", "user": {"login": "octocat"}}`)
+	})
+	mr, err := github.FindMergeRequest(12)
+	assert.NoError(t, err)
+	assert.Equal(t, MergeRequestInfo{Number: 12, Ref: "#12", Title: "feat: something", URL: "https://github.com/my/testrepo/pull/12", Author: "octocat"}, mr)
+	_, err = github.FindMergeRequest(13)
+	assert.Error(t, err)
+
+	testmux.HandleFunc("/repos/my/testrepo/commits/abc123/pulls", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"number": 12, "title": "feat: something", "html_url": "https://github.com/my/testrepo/pull/12", "user": {"login": "octocat"}}]`)
+	})
+	testmux.HandleFunc("/repos/my/testrepo/commits/def456/pulls", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mr, err = github.FindCommitMergeRequest("abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, "#12", mr.Ref)
+	assert.Equal(t, "octocat", mr.Author)
+	_, err = github.FindCommitMergeRequest("def456")
+	assert.ErrorIs(t, err, errNoMergeRequestFound)
+
+	testmux.HandleFunc("/repos/my/testrepo/issues/12/comments", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("page") == "1" {
+				fmt.Fprint(w, "["+strings.TrimSuffix(strings.Repeat(`{"body": "comment"},`, commentsPerPage), ",")+"]")
+				return
+			}
+			fmt.Fprint(w, `[{"body": "last"}]`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	assert.NoError(t, github.CommentMergeRequest(12, "comment"))
+	assert.Error(t, github.CommentMergeRequest(13, "comment"))
+	comments, err := github.MergeRequestComments(12)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, commentsPerPage+1) {
+		assert.Equal(t, "comment", comments[0])
+		assert.Equal(t, "last", comments[commentsPerPage])
+	}
+	_, err = github.MergeRequestComments(13)
+	assert.Error(t, err)
+
+	testmux.HandleFunc("/repos/my/testrepo/commits", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("author") {
+		case "octocat@example.com":
+			fmt.Fprint(w, `[{"sha": "abc123", "author": {"login": "octocat"}}]`)
+		case "unlinked@example.com":
+			fmt.Fprint(w, `[{"sha": "abc123", "author": null}]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	})
+	handle, err := github.FindUser("octocat@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "octocat", handle)
+	_, err = github.FindUser("unlinked@example.com")
+	assert.ErrorIs(t, err, errNoUserFound)
+	_, err = github.FindUser("unknown@example.com")
+	assert.ErrorIs(t, err, errNoUserFound)
 }
@@ -15,15 +15,18 @@ type Gitlab struct {
 	server string
 	token  string
 	repo   string
+	// releaseBranch is the source branch of the release merge request
+	releaseBranch string
 }
 
 var _ Backend = Gitlab{}
 
-func NewGitlabBackend(token, server, repo string) Gitlab {
+func NewGitlabBackend(token, server, repo, releaseBranch string) Gitlab {
 	return Gitlab{
-		server: "https://" + server,
-		token:  token,
-		repo:   repo,
+		server:        "https://" + server,
+		token:         token,
+		repo:          repo,
+		releaseBranch: releaseBranch,
 	}
 }
 
@@ -44,6 +47,9 @@ func (gitlab Gitlab) request(method, endpoint string, expectedStatus int, body i
 	defer resp.Body.Close()
 	if resp.StatusCode != expectedStatus {
 		b, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: expected status is %d: %v %s", errNotFound, expectedStatus, resp, string(b))
+		}
 		return fmt.Errorf("expected status is %d: %v %s", expectedStatus, resp, string(b))
 	}
 	if target == nil {
@@ -65,12 +71,12 @@ func (gitlab Gitlab) findOpenMergeRequest() (int, error) {
 		State        string `json:"state"`
 	}
 
-	if err := gitlab.request(http.MethodGet, fmt.Sprintf("projects/%s/merge_requests?state=opened&source_branch=semanticore%%2frelease", url.PathEscape(gitlab.repo)), http.StatusOK, nil, &mrs); err != nil {
+	if err := gitlab.request(http.MethodGet, fmt.Sprintf("projects/%s/merge_requests?state=opened&source_branch=%s", url.PathEscape(gitlab.repo), url.QueryEscape(gitlab.releaseBranch)), http.StatusOK, nil, &mrs); err != nil {
 		return 0, fmt.Errorf("unable to get merge requests: %w", err)
 	}
 
 	for _, mr := range mrs {
-		if mr.SourceBranch == "semanticore/release" && mr.State == "opened" {
+		if mr.SourceBranch == gitlab.releaseBranch && mr.State == "opened" {
 			log.Printf("[gitlab] merge request found: %d", mr.IID)
 			return mr.IID, nil
 		}
@@ -79,6 +85,14 @@ func (gitlab Gitlab) findOpenMergeRequest() (int, error) {
 	return 0, errNoMergeRequestFound
 }
 
+func (gitlab Gitlab) ReleaseMergeRequest() (int, error) {
+	iid, err := gitlab.findOpenMergeRequest()
+	if errors.Is(err, errNoMergeRequestFound) {
+		return 0, nil
+	}
+	return iid, err
+}
+
 func (gitlab Gitlab) CloseMergeRequest() error {
 	iid, err := gitlab.findOpenMergeRequest()
 	if errors.Is(err, errNoMergeRequestFound) {
@@ -100,7 +114,7 @@ func (gitlab Gitlab) MergeRequest(target, title, description, labels string) err
 	}
 
 	data := make(url.Values)
-	data.Set("source_branch", "semanticore/release")
+	data.Set("source_branch", gitlab.releaseBranch)
 	data.Set("target_branch", target)
 	data.Set("title", title)
 	data.Set("description", description)
@@ -115,19 +129,32 @@ func (gitlab Gitlab) MergeRequest(target, title, description, labels string) err
 }
 
 func (gitlab Gitlab) Release(tag, ref, changelog string) error {
-	data := make(url.Values)
-	data.Set("tag_name", tag)
-	data.Set("ref", ref)
-	if err := gitlab.request(http.MethodPost, fmt.Sprintf("projects/%s/repository/tags", url.PathEscape(gitlab.repo)), http.StatusCreated, strings.NewReader(data.Encode()), nil); err != nil {
-		return fmt.Errorf("unable to tag release %s on %s: %w", tag, ref, err)
+	if ref != "" {
+		data := make(url.Values)
+		data.Set("tag_name", tag)
+		data.Set("ref", ref)
+		if err := gitlab.request(http.MethodPost, fmt.Sprintf("projects/%s/repository/tags", url.PathEscape(gitlab.repo)), http.StatusCreated, strings.NewReader(data.Encode()), nil); err != nil {
+			return fmt.Errorf("unable to tag release %s on %s: %w", tag, ref, err)
+		}
 	}
 
-	data = make(url.Values)
+	data := make(url.Values)
 	data.Set("tag_name", tag)
 	data.Set("description", changelog)
 	return gitlab.request(http.MethodPost, fmt.Sprintf("projects/%s/releases", url.PathEscape(gitlab.repo)), http.StatusCreated, strings.NewReader(data.Encode()), nil)
 }
 
+func (gitlab Gitlab) HasRelease(tag string) (bool, error) {
+	err := gitlab.request(http.MethodGet, fmt.Sprintf("projects/%s/releases/%s", url.PathEscape(gitlab.repo), url.PathEscape(tag)), http.StatusOK, nil, nil)
+	if errors.Is(err, errNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to get release %s: %w", tag, err)
+	}
+	return true, nil
+}
+
 func (gitlab Gitlab) MainBranch() (string, error) {
 	var repo struct {
 		DefaultBranch string `json:"default_branch"`
@@ -140,6 +167,89 @@ func (gitlab Gitlab) MainBranch() (string, error) {
 	return repo.DefaultBranch, nil
 }
 
+type gitlabMergeRequest struct {
+	IID    int    `json:"iid"`
+	Title  string `json:"title"`
+	WebURL string `json:"web_url"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+func (mr gitlabMergeRequest) info() MergeRequestInfo {
+	return MergeRequestInfo{
+		Number: mr.IID,
+		Ref:    fmt.Sprintf("!%d", mr.IID),
+		Title:  mr.Title,
+		URL:    mr.WebURL,
+		Author: mr.Author.Username,
+	}
+}
+
+func (gitlab Gitlab) FindMergeRequest(number int) (MergeRequestInfo, error) {
+	var mr gitlabMergeRequest
+	if err := gitlab.request(http.MethodGet, fmt.Sprintf("projects/%s/merge_requests/%d", url.PathEscape(gitlab.repo), number), http.StatusOK, nil, &mr); err != nil {
+		return MergeRequestInfo{}, fmt.Errorf("unable to get merge request %d: %w", number, err)
+	}
+	return mr.info(), nil
+}
+
+func (gitlab Gitlab) FindCommitMergeRequest(sha string) (MergeRequestInfo, error) {
+	var mrs []gitlabMergeRequest
+	if err := gitlab.request(http.MethodGet, fmt.Sprintf("projects/%s/repository/commits/%s/merge_requests", url.PathEscape(gitlab.repo), sha), http.StatusOK, nil, &mrs); err != nil {
+		return MergeRequestInfo{}, fmt.Errorf("unable to get merge requests of commit %s: %w", sha, err)
+	}
+	if len(mrs) == 0 {
+		return MergeRequestInfo{}, errNoMergeRequestFound
+	}
+	return mrs[0].info(), nil
+}
+
+func (gitlab Gitlab) CommentMergeRequest(number int, body string) error {
+	data := make(url.Values)
+	data.Set("body", body)
+	return gitlab.request(http.MethodPost, fmt.Sprintf("projects/%s/merge_requests/%d/notes", url.PathEscape(gitlab.repo), number), http.StatusCreated, strings.NewReader(data.Encode()), nil)
+}
+
+func (gitlab Gitlab) MergeRequestComments(number int) ([]string, error) {
+	var bodies []string
+	for page := 1; ; page++ {
+		var notes []struct {
+			Body string `json:"body"`
+		}
+		if err := gitlab.request(http.MethodGet, fmt.Sprintf("projects/%s/merge_requests/%d/notes?per_page=%d&page=%d", url.PathEscape(gitlab.repo), number, commentsPerPage, page), http.StatusOK, nil, &notes); err != nil {
+			return nil, fmt.Errorf("unable to get notes of merge request %d: %w", number, err)
+		}
+		for _, note := range notes {
+			bodies = append(bodies, note.Body)
+		}
+		if len(notes) < commentsPerPage {
+			return bodies, nil
+		}
+	}
+}
+
+func (gitlab Gitlab) FindUser(email string) (string, error) {
+	var users []struct {
+		Username string `json:"username"`
+	}
+	if err := gitlab.request(http.MethodGet, "users?search="+url.QueryEscape(email), http.StatusOK, nil, &users); err != nil {
+		return "", fmt.Errorf("unable to search user %s: %w", email, err)
+	}
+	if len(users) == 0 {
+		return "", errNoUserFound
+	}
+	return users[0].Username, nil
+}
+
+func (gitlab Gitlab) Links() Links {
+	return Links{
+		Issue:   gitlab.server + "/" + gitlab.repo + "/-/issues/{id}",
+		Commit:  gitlab.server + "/" + gitlab.repo + "/-/commit/{hash}",
+		Compare: gitlab.server + "/" + gitlab.repo + "/-/compare/{from}...{to}",
+	}
+}
+
 func (gitlab Gitlab) SetAuth(r *http.Request) {
 	r.SetBasicAuth("gitlab-ci-token", gitlab.token)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +15,7 @@ func TestGitlab(t *testing.T) {
 	testserver := httptest.NewServer(testmux)
 	defer testserver.Close()
 
-	gitlab := NewGitlabBackend("test-token", "server", "my/test/repo")
+	gitlab := NewGitlabBackend("test-token", "server", "my/test/repo", DefaultReleaseBranch)
 
 	gitlab.server = testserver.URL
 	assert.Error(t, gitlab.request(http.MethodGet, "notfound", http.StatusAccepted, nil, nil))
@@ -35,12 +36,14 @@ func TestGitlab(t *testing.T) {
 	assert.Error(t, gitlab.request(http.MethodGet, "brokenbody", http.StatusOK, nil, &body))
 
 	noMrs := true
+	queriedBranch := ""
 	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/merge_requests", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			w.WriteHeader(http.StatusCreated)
 			return
 		}
-		if noMrs {
+		queriedBranch = r.URL.Query().Get("source_branch")
+		if noMrs || queriedBranch != "semanticore/release" {
 			fmt.Fprint(w, `[]`)
 			return
 		}
@@ -61,6 +64,18 @@ func TestGitlab(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 3, num)
 
+	otherBranch := NewGitlabBackend("test-token", "server", "my/test/repo", "chore/release")
+	otherBranch.server = testserver.URL
+	_, err = otherBranch.findOpenMergeRequest()
+	assert.ErrorIs(t, err, errNoMergeRequestFound)
+	assert.Equal(t, "chore/release", queriedBranch)
+	num, err = otherBranch.ReleaseMergeRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, num)
+	num, err = gitlab.ReleaseMergeRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, num)
+
 	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/merge_requests/3", func(w http.ResponseWriter, r *http.Request) {})
 	assert.NoError(t, gitlab.CloseMergeRequest())
 
@@ -68,13 +83,28 @@ func TestGitlab(t *testing.T) {
 	noMrs = true
 	assert.NoError(t, gitlab.MergeRequest("main", "Release v1.2.3", "release description", "tag1,tag2"))
 
+	tags := 0
 	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/repository/tags", func(w http.ResponseWriter, r *http.Request) {
+		tags++
 		w.WriteHeader(http.StatusCreated)
 	})
 	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/releases", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusCreated)
 	})
 	assert.NoError(t, gitlab.Release("v1.2.3", "abc123", "changelog"))
+	assert.Equal(t, 1, tags)
+	assert.NoError(t, gitlab.Release("v1.2.3", "", "changelog"))
+	assert.Equal(t, 1, tags)
+
+	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/releases/v1.2.3", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "v1.2.3"}`)
+	})
+	exists, err := gitlab.HasRelease("v1.2.3")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = gitlab.HasRelease("v1.2.4")
+	assert.NoError(t, err)
+	assert.False(t, exists)
 
 	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, `{"default_branch": "main"}`)
@@ -82,4 +112,67 @@ func TestGitlab(t *testing.T) {
 	branch, err := gitlab.MainBranch()
 	assert.NoError(t, err)
 	assert.Equal(t, "main", branch)
+
+	assert.Equal(t, Links{
+		Issue:   testserver.URL + "/my/test/repo/-/issues/{id}",
+		Commit:  testserver.URL + "/my/test/repo/-/commit/{hash}",
+		Compare: testserver.URL + "/my/test/repo/-/compare/{from}...{to}",
+	}, gitlab.Links())
+
+	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/merge_requests/12", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"iid": 12, "title": "feat: something", "web_url": "https://gitlab.com/my/test/repo/-/merge_requests/12", "author": {"username": "tanuki"}}`)
+	})
+	mr, err := gitlab.FindMergeRequest(12)
+	assert.NoError(t, err)
+	assert.Equal(t, MergeRequestInfo{Number: 12, Ref: "!12", Title: "feat: something", URL: "https://gitlab.com/my/test/repo/-/merge_requests/12", Author: "tanuki"}, mr)
+	_, err = gitlab.FindMergeRequest(13)
+	assert.Error(t, err)
+
+	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/repository/commits/abc123/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"iid": 12, "title": "feat: something", "web_url": "https://gitlab.com/my/test/repo/-/merge_requests/12", "author": {"username": "tanuki"}}]`)
+	})
+	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/repository/commits/def456/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mr, err = gitlab.FindCommitMergeRequest("abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, "!12", mr.Ref)
+	assert.Equal(t, "tanuki", mr.Author)
+	_, err = gitlab.FindCommitMergeRequest("def456")
+	assert.ErrorIs(t, err, errNoMergeRequestFound)
+
+	testmux.HandleFunc("/api/v4/projects/my%2ftest%2frepo/merge_requests/12/notes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("page") == "1" {
+				fmt.Fprint(w, "["+strings.TrimSuffix(strings.Repeat(`{"body": "comment"},`, commentsPerPage), ",")+"]")
+				return
+			}
+			fmt.Fprint(w, `[{"body": "last"}]`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	assert.NoError(t, gitlab.CommentMergeRequest(12, "comment"))
+	assert.Error(t, gitlab.CommentMergeRequest(13, "comment"))
+	comments, err := gitlab.MergeRequestComments(12)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, commentsPerPage+1) {
+		assert.Equal(t, "comment", comments[0])
+		assert.Equal(t, "last", comments[commentsPerPage])
+	}
+	_, err = gitlab.MergeRequestComments(13)
+	assert.Error(t, err)
+
+	testmux.HandleFunc("/api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("search") == "jane@example.com" {
+			fmt.Fprint(w, `[{"id": 1, "username": "jane"}]`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})
+	handle, err := gitlab.FindUser("jane@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "jane", handle)
+	_, err = gitlab.FindUser("unknown@example.com")
+	assert.ErrorIs(t, err, errNoUserFound)
 }
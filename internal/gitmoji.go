@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// gitmoji maps an emoji and its shortcode to a commit type, see https://gitmoji.dev
+type gitmoji struct {
+	emoji    string
+	code     string
+	typ      CommitType
+	breaking bool
+}
+
+// gitmojis are stored without the U+FE0F variation selector, which is optional in commit messages
+var gitmojis = []gitmoji{
+	{"✨", "sparkles", TypeFeat, false},
+	{"🎉", "tada", TypeFeat, false},
+	{"🚸", "children_crossing", TypeFeat, false},
+	{"💥", "boom", TypeFeat, true},
+	{"🐛", "bug", TypeFix, false},
+	{"🚑", "ambulance", TypeFix, false},
+	{"🩹", "adhesive_bandage", TypeFix, false},
+	{"🥅", "goal_net", TypeFix, false},
+	{"🔒", "lock", TypeSecurity, false},
+	{"🛂", "passport_control", TypeSecurity, false},
+	{"⚡", "zap", TypePerf, false},
+	{"♻", "recycle", TypeRefactor, false},
+	{"🎨", "art", TypeRefactor, false},
+	{"🏗", "building_construction", TypeRefactor, false},
+	{"🔥", "fire", TypeRefactor, false},
+	{"⚰", "coffin", TypeRefactor, false},
+	{"📝", "memo", TypeDocs, false},
+	{"💡", "bulb", TypeDocs, false},
+	{"✅", "white_check_mark", TypeTest, false},
+	{"🧪", "test_tube", TypeTest, false},
+	{"👷", "construction_worker", TypeOps, false},
+	{"💚", "green_heart", TypeOps, false},
+	{"🚀", "rocket", TypeOps, false},
+	{"📦", "package", TypeOps, false},
+	{"🐳", "whale", TypeOps, false},
+	{"🔧", "wrench", TypeChore, false},
+	{"🔨", "hammer", TypeChore, false},
+	{"⬆", "arrow_up", TypeChore, false},
+	{"⬇", "arrow_down", TypeChore, false},
+	{"📌", "pushpin", TypeChore, false},
+	{"➕", "heavy_plus_sign", TypeChore, false},
+	{"➖", "heavy_minus_sign", TypeChore, false},
+	{"🚨", "rotating_light", TypeChore, false},
+	{"🙈", "see_no_evil", TypeChore, false},
+	{"🗑", "wastebasket", TypeChore, false},
+}
+
+var gitmojiShortcodeRegexp = regexp.MustCompile(`^:([a-z0-9_+-]+):`)
+var gitmojiScopeRegexp = regexp.MustCompile(`^\(([^)]*)\)\s*:?\s*`)
+
+// ParseGitmojiCommit parses commits starting with a gitmoji like `✨ add export` or `:bug: fix crash`,
+// the emoji may be followed by a scope as in `✨ (api): add export` or by a conventional commit.
+// Messages without a known gitmoji are parsed by ParseCommit.
+func ParseGitmojiCommit(msg string) ConventionalCommit {
+	commit, ok := parseGitmoji(msg)
+	if !ok {
+		return ParseCommit(msg)
+	}
+	return commit
+}
+
+func parseGitmoji(msg string) (ConventionalCommit, bool) {
+	mapping, rest, ok := gitmojiPrefix(strings.TrimSpace(msg))
+	if !ok {
+		return ConventionalCommit{}, false
+	}
+
+	// a conventional commit after the emoji keeps its type, e.g. `💥 feat(api): remove v1`
+	if lintConventionalRegexp.MatchString(rest) {
+		if commit := ParseCommit(rest); commit.Type != TypeOther {
+			commit.Breaking = commit.Breaking || mapping.breaking
+			return commit, true
+		}
+	}
+
+	header := string(mapping.typ)
+	if match := gitmojiScopeRegexp.FindStringSubmatch(rest); match != nil {
+		header += "(" + match[1] + ")"
+		rest = rest[len(match[0]):]
+	}
+	// an emoji without description on the subject line is not a gitmoji commit, e.g. `✨` or `✨ (ui)`
+	if strings.TrimSpace(strings.SplitN(rest, "\n", 2)[0]) == "" {
+		return ConventionalCommit{}, false
+	}
+	commit := ParseCommit(header + ": " + rest)
+	commit.Breaking = commit.Breaking || mapping.breaking
+	return commit, true
+}
+
+// gitmojiPrefix returns the gitmoji at the start of msg and the remaining message starting at the description
+func gitmojiPrefix(msg string) (gitmoji, string, bool) {
+	if match := gitmojiShortcodeRegexp.FindStringSubmatch(msg); match != nil {
+		for _, mapping := range gitmojis {
+			if mapping.code == match[1] {
+				return mapping, strings.TrimLeft(msg[len(match[0]):], " \t"), true
+			}
+		}
+		return gitmoji{}, "", false
+	}
+	for _, mapping := range gitmojis {
+		if rest, ok := strings.CutPrefix(msg, mapping.emoji); ok {
+			return mapping, strings.TrimLeft(strings.TrimPrefix(rest, "\ufe0f"), " \t"), true
+		}
+	}
+	return gitmoji{}, "", false
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitmojiCommit(t *testing.T) {
+	tests := []struct {
+		msg      string
+		typ      CommitType
+		scope    string
+		desc     string
+		breaking bool
+	}{
+		{"✨ add export", TypeFeat, "", "add export", false},
+		{":sparkles: add export", TypeFeat, "", "add export", false},
+		{":bug: fix crash", TypeFix, "", "fix crash", false},
+		{"🐛(api): fix crash", TypeFix, "api", "fix crash", false},
+		{"✨ (ui) add button", TypeFeat, "ui", "add button", false},
+		{"♻️ simplify parser", TypeRefactor, "", "simplify parser", false},
+		{"♻ simplify parser", TypeRefactor, "", "simplify parser", false},
+		{"⚡️ cache tags", TypePerf, "", "cache tags", false},
+		{"🔒️ update tls config", TypeSecurity, "", "update tls config", false},
+		{"💥 remove v1 api", TypeFeat, "", "remove v1 api", true},
+		{":boom: fix(api): remove v1 fallback", TypeFix, "api", "remove v1 fallback", true},
+		{"🐛 feat: conventional type wins", TypeFeat, "", "conventional type wins", false},
+		{"📝 update readme\n\nRefs: #12", TypeDocs, "", "update readme", false},
+		{"feat: plain conventional commit", TypeFeat, "", "plain conventional commit", false},
+		{":unknown: something", TypeOther, "", ":unknown: something", false},
+		{"🦄 something", TypeOther, "", "🦄 something", false},
+		{"✨", TypeOther, "", "✨", false},
+		{":sparkles: ", TypeOther, "", ":sparkles:", false},
+		{"✨ (ui)", TypeOther, "", "✨ (ui)", false},
+		{"✨\n\nadd export", TypeOther, "", "✨", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			commit := ParseGitmojiCommit(test.msg)
+			assert.Equal(t, test.typ, commit.Type)
+			assert.Equal(t, test.scope, commit.Scope)
+			assert.Equal(t, test.desc, commit.Description)
+			assert.Equal(t, test.breaking, commit.Breaking)
+		})
+	}
+
+	assert.Equal(t, []string{"#12"}, ParseGitmojiCommit("📝 update readme\n\nRefs: #12").IssueRefs())
+}
+
+func TestLintMessageGitmoji(t *testing.T) {
+	assert.Len(t, LintMessage("✨ add export", LintRules{}), 1)
+	assert.Empty(t, LintMessage("✨ add export", LintRules{Gitmoji: true}))
+	assert.Empty(t, LintMessage("feat: add export", LintRules{Gitmoji: true}))
+	assert.Len(t, LintMessage("🦄 add export", LintRules{Gitmoji: true}), 1)
+	assert.Len(t, LintMessage("✨", LintRules{Gitmoji: true}), 1)
+	assert.Len(t, LintMessage("✨ add export", LintRules{Gitmoji: true, RequireScope: true}), 1)
+}
@@ -55,7 +55,7 @@ func TestNpmUpdateVersionHook(t *testing.T) {
 	mockWt.Add("package.json")
 	testCommit("test(semanticore): initial commit")
 
-	repository, err := internal.ReadRepository(mockRepo, true)
+	repository, err := internal.ReadRepository(mockRepo, internal.Options{CreateMajor: true})
 	assert.NoError(t, err)
 	repository.Major = 4
 	repository.Minor = 5
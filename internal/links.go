@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Links are URL templates used to render references in the changelog
+type Links struct {
+	// Issue links forge issues like `#123`, `{id}` is replaced by the issue number
+	Issue string
+	// Tracker links issues of an external tracker like `PROJ-42`, `{id}` is replaced by the issue key
+	Tracker string
+	// Commit links commit hashes, `{hash}` is replaced by the full hash
+	Commit string
+	// Compare links the diff between two versions, `{from}` and `{to}` are replaced by the tags
+	Compare string
+}
+
+// Merge returns the links with empty templates taken from the fallback
+func (links Links) Merge(fallback Links) Links {
+	if links.Issue == "" {
+		links.Issue = fallback.Issue
+	}
+	if links.Tracker == "" {
+		links.Tracker = fallback.Tracker
+	}
+	if links.Commit == "" {
+		links.Commit = fallback.Commit
+	}
+	if links.Compare == "" {
+		links.Compare = fallback.Compare
+	}
+	return links
+}
+
+func (links Links) issue(ref string) string {
+	template, id := links.Tracker, ref
+	if strings.HasPrefix(ref, "#") {
+		template, id = links.Issue, strings.TrimPrefix(ref, "#")
+	}
+	if template == "" {
+		return ref
+	}
+	return fmt.Sprintf("[%s](%s)", ref, strings.ReplaceAll(template, "{id}", id))
+}
+
+// commit renders the abbreviated hash, linked if a commit template is configured
+func (links Links) commit(hash string) string {
+	if links.Commit == "" {
+		return hash[:8]
+	}
+	return fmt.Sprintf("[%s](%s)", hash[:8], strings.ReplaceAll(links.Commit, "{hash}", hash))
+}
+
+// compare returns the URL of the diff between two tags, it is empty without a compare template or previous tag
+func (links Links) compare(from, to string) string {
+	if links.Compare == "" || from == "" {
+		return ""
+	}
+	return strings.NewReplacer("{from}", from, "{to}", to).Replace(links.Compare)
+}
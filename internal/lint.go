@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// LintRules configure the checks applied to commit messages
+type LintRules struct {
+	// Scopes lists the allowed scopes, any scope is allowed if empty
+	Scopes []string
+	// ScopeAliases normalises scopes before they are checked
+	ScopeAliases map[string]string
+	// RequireScope rejects commits without scope
+	RequireScope bool
+	// MaxDescriptionLength limits the length of the description, 0 disables the check
+	MaxDescriptionLength int
+	// Gitmoji accepts commits starting with a gitmoji like `✨ add export` instead of a type
+	Gitmoji bool
+	// ReleaseCommitTemplate accepts release commits, DefaultReleaseCommitTemplate is used if empty
+	ReleaseCommitTemplate string
+}
+
+// LintResult contains the problems found in a commit message
+type LintResult struct {
+	Commit   string
+	Subject  string
+	Problems []string
+}
+
+var lintConventionalRegexp = regexp.MustCompile(`^[a-zA-Z]+(\([^)]*\))?!?: \S`)
+var lintBreakingChangeRegexp = regexp.MustCompile(`(?i)^breaking[ _-]?changes?\s*:`)
+var lintBreakingChangeTokenRegexp = regexp.MustCompile(`^BREAKING[ -]CHANGE: `)
+
+// LintMessage checks a commit message and returns the problems found
+func LintMessage(msg string, rules LintRules) []string {
+	msg = strings.TrimSpace(msg)
+	subject := subject(msg)
+	if _, major, minor, patch := DetectReleaseCommit(msg, false, rules.ReleaseCommitTemplate); major+minor+patch > 0 {
+		return nil
+	}
+	if revertSubjectRegexp.MatchString(subject) {
+		return nil
+	}
+
+	var problems []string
+	commit := ParseCommit(msg)
+	gitmoji := false
+	if rules.Gitmoji {
+		commit, gitmoji = parseGitmoji(msg)
+		if !gitmoji {
+			commit = ParseCommit(msg)
+		}
+	}
+	commit.Scope = normalizeScope(commit.Scope, rules.ScopeAliases)
+	if commit.Type == TypeOther {
+		problems = append(problems, fmt.Sprintf("unknown commit type, use one of %s", strings.Join(lintTypes, ", ")))
+	} else if !gitmoji && !lintConventionalRegexp.MatchString(subject) {
+		problems = append(problems, "subject does not match `type(scope): description`")
+	}
+
+	if commit.Type != TypeOther {
+		if commit.Scope == "" && rules.RequireScope {
+			problems = append(problems, "missing scope")
+		}
+		if commit.Scope != "" && len(rules.Scopes) > 0 && !slices.Contains(rules.Scopes, commit.Scope) {
+			problems = append(problems, fmt.Sprintf("unknown scope %q, use one of %s", commit.Scope, strings.Join(rules.Scopes, ", ")))
+		}
+		if rules.MaxDescriptionLength > 0 && len([]rune(unescapeSpecialChars.Replace(commit.Description))) > rules.MaxDescriptionLength {
+			problems = append(problems, fmt.Sprintf("description is longer than %d characters", rules.MaxDescriptionLength))
+		}
+	}
+
+	for _, line := range strings.Split(msg, "\n")[1:] {
+		if lintBreakingChangeRegexp.MatchString(line) && !lintBreakingChangeTokenRegexp.MatchString(line) {
+			problems = append(problems, fmt.Sprintf("invalid breaking change footer %q, use `BREAKING CHANGE: <description>`", line))
+		}
+	}
+	for _, footer := range commit.Footers {
+		if isBreakingChangeToken(footer.Token) && footer.Value == "" {
+			problems = append(problems, "breaking change footer without description")
+		}
+	}
+
+	return problems
+}
+
+// lintTypes lists the type prefixes of commitTypePrefixes
+var lintTypes = func() []string {
+	var types []string
+	for _, mapping := range commitTypePrefixes {
+		types = append(types, mapping.prefixes...)
+	}
+	return types
+}()
+
+// LintCommits checks all non-merge commits of HEAD which are not reachable from target, like `git log target..HEAD`.
+// Shallow clones are supported, the history is only read up to the shallow boundary.
+func LintCommits(repo *git.Repository, target plumbing.Hash, rules LintRules) ([]LintResult, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("repo.Head() failed: %w", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read head commit: %w", err)
+	}
+	targetCommit, err := repo.CommitObject(target)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read target commit %s: %w", target, err)
+	}
+	shallow, err := shallowCommits(repo)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := commitRange(targetCommit, headCommit, missingParents(repo, shallow))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []LintResult
+	for _, c := range commits {
+		if len(c.ParentHashes) > 1 {
+			continue
+		}
+		results = append(results, LintResult{
+			Commit:   c.Hash.String(),
+			Subject:  subject(c.Message),
+			Problems: LintMessage(c.Message, rules),
+		})
+	}
+	return results, nil
+}
+
+// FormatLintResults renders the results as markdown and returns whether any problem was found
+func FormatLintResults(results []LintResult) (string, bool) {
+	var b strings.Builder
+	failed := 0
+	for _, result := range results {
+		if len(result.Problems) == 0 {
+			fmt.Fprintf(&b, "- ✅ `%s` %s\n", result.Commit[:8], result.Subject)
+			continue
+		}
+		failed++
+		fmt.Fprintf(&b, "- ❌ `%s` %s\n", result.Commit[:8], result.Subject)
+		for _, problem := range result.Problems {
+			fmt.Fprintf(&b, "  - %s\n", problem)
+		}
+	}
+	fmt.Fprintf(&b, "\n%d of %d commits failed the commit message checks.\n", failed, len(results))
+	return b.String(), failed > 0
+}
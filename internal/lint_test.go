@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintMessage(t *testing.T) {
+	rules := LintRules{Scopes: []string{"api", "ui"}, MaxDescriptionLength: 20}
+	var cases = []struct {
+		msg      string
+		rules    LintRules
+		problems int
+	}{
+		{"feat(api): csv export", rules, 0},
+		{"fix: crash", rules, 0},
+		{"fix: crash", LintRules{RequireScope: true}, 1},
+		{"Release v1.2.3", rules, 0},
+		{`Revert "feat: csv export"`, rules, 0},
+		{"update readme", rules, 1},
+		{"feat:", rules, 1},
+		{"fix crash", rules, 1},
+		{"feat(cli): csv export", rules, 1},
+		{"feat(api): a very long description of the export", rules, 1},
+		{"fix(api): use <T> & <U> here", rules, 0},
+		{"rework(ui): layout", rules, 0},
+		{"feat(api): csv export\n\nBREAKING CHANGE: columns changed", rules, 0},
+		{"feat(api): csv export\n\nbreaking change: columns changed", rules, 1},
+		{"feat(api): csv export\n\nBREAKING CHANGES: columns changed", rules, 1},
+		{"feat(cli)!: a very long description of the export\n\nBreaking-Change: columns changed", rules, 3},
+	}
+
+	for _, c := range cases {
+		problems := LintMessage(c.msg, c.rules)
+		assert.Len(t, problems, c.problems, "%q: %v", c.msg, problems)
+	}
+	assert.Equal(t, []string{"unknown commit type, use one of fix, bug, feat, test, chore, update, ops, ci, cd, build, doc, perf, refactor, rework, sec"}, LintMessage("wip: things", rules))
+}
+
+func TestLintCommits(t *testing.T) {
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+
+	testCommit("initial commit")
+	main := testCommit("wip on main")
+	testCommit("feat: good commit")
+	testCommit("bad commit")
+	head, err := mockRepo.Head()
+	assert.NoError(t, err)
+	_, err = mockWt.Commit("Merge branch 'main' into 'feature'", &git.CommitOptions{Parents: []plumbing.Hash{head.Hash(), main}, AllowEmptyCommits: true})
+	assert.NoError(t, err)
+
+	results, err := LintCommits(mockRepo, main, LintRules{})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "bad commit", results[0].Subject)
+	assert.Len(t, results[0].Problems, 1)
+	assert.Equal(t, "feat: good commit", results[1].Subject)
+	assert.Empty(t, results[1].Problems)
+
+	report, failed := FormatLintResults(results)
+	assert.True(t, failed)
+	assert.Contains(t, report, "- ❌ `"+results[0].Commit[:8]+"` bad commit\n  - unknown commit type")
+	assert.Contains(t, report, "1 of 2 commits failed")
+
+	_, failed = FormatLintResults(results[1:])
+	assert.False(t, failed)
+}
+
+func TestLintCommitsShallow(t *testing.T) {
+	remoteDir := t.TempDir()
+	_, err := git.PlainInit(remoteDir, true)
+	assert.NoError(t, err)
+
+	mockRepo, _, testCommit := newTestRepository(t)
+	for _, msg := range []string{"initial commit", "wip on main", "fix: main fix"} {
+		testCommit(msg)
+	}
+	main := testCommit("docs: main docs")
+	testCommit("bad commit")
+	testCommit("feat: good commit")
+	_, err = mockRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}})
+	assert.NoError(t, err)
+	assert.NoError(t, mockRepo.Push(&git.PushOptions{RemoteName: "origin"}))
+
+	clone, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{URL: remoteDir, Depth: 3})
+	assert.NoError(t, err)
+	results, err := LintCommits(clone, main, LintRules{})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "feat: good commit", results[0].Subject)
+		assert.Equal(t, "bad commit", results[1].Subject)
+	}
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"errors"
+	"log"
+	"sync"
+)
+
+// mergeRequestWorkers limits the concurrent backend requests when looking up merge requests
+const mergeRequestWorkers = 8
+
+// mergeRequestCache looks up merge requests by commit or number through the backend, each is requested only once
+type mergeRequestCache struct {
+	backend Backend
+
+	mu            sync.Mutex
+	mergeRequests map[string]*MergeRequestInfo
+	numbers       map[int]*MergeRequestInfo
+}
+
+func newMergeRequestCache(backend Backend) *mergeRequestCache {
+	return &mergeRequestCache{
+		backend:       backend,
+		mergeRequests: make(map[string]*MergeRequestInfo),
+		numbers:       make(map[int]*MergeRequestInfo),
+	}
+}
+
+// resolve looks up the merge requests of all given commits concurrently
+func (cache *mergeRequestCache) resolve(shas []string) {
+	concurrently(shas, func(sha string) { cache.lookup(sha) })
+}
+
+// resolveNumbers looks up the merge requests with the given numbers concurrently
+func (cache *mergeRequestCache) resolveNumbers(numbers []int) {
+	concurrently(numbers, func(number int) { cache.lookupNumber(number) })
+}
+
+// concurrently calls fn for all items with mergeRequestWorkers workers
+func concurrently[T any](items []T, fn func(T)) {
+	queue := make(chan T)
+	var wg sync.WaitGroup
+	for i := 0; i < mergeRequestWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				fn(item)
+			}
+		}()
+	}
+	for _, item := range items {
+		queue <- item
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// lookup returns the merge request of a commit, or nil if there is none
+func (cache *mergeRequestCache) lookup(sha string) *MergeRequestInfo {
+	cache.mu.Lock()
+	mr, ok := cache.mergeRequests[sha]
+	cache.mu.Unlock()
+	if ok {
+		return mr
+	}
+
+	info, err := cache.backend.FindCommitMergeRequest(sha)
+	if err == nil {
+		mr = &info
+	} else if !errors.Is(err, errNoMergeRequestFound) {
+		log.Printf("[semanticore] unable to look up merge request of %s: %s", sha, err)
+	}
+
+	cache.mu.Lock()
+	cache.mergeRequests[sha] = mr
+	cache.mu.Unlock()
+	return mr
+}
+
+// lookupNumber returns the merge request with the given number, or nil if the lookup failed
+func (cache *mergeRequestCache) lookupNumber(number int) *MergeRequestInfo {
+	cache.mu.Lock()
+	mr, ok := cache.numbers[number]
+	cache.mu.Unlock()
+	if ok {
+		return mr
+	}
+
+	info, err := cache.backend.FindMergeRequest(number)
+	if err == nil {
+		mr = &info
+	} else {
+		log.Printf("[semanticore] unable to look up merge request %d: %s", number, err)
+	}
+
+	cache.mu.Lock()
+	cache.numbers[number] = mr
+	cache.mu.Unlock()
+	return mr
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// pathPattern matches file paths against a glob, `**` matches any number of directories,
+// patterns without a `/` match the file name in any directory
+type pathPattern struct {
+	regexp   *regexp.Regexp
+	basename bool
+}
+
+func compilePathPatterns(patterns []string) ([]pathPattern, error) {
+	var compiled []pathPattern
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "/")
+		if pattern == "" {
+			continue
+		}
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+
+		var expr strings.Builder
+		expr.WriteString("^")
+		for i := 0; i < len(pattern); i++ {
+			switch {
+			case strings.HasPrefix(pattern[i:], "**/"):
+				expr.WriteString("(.*/)?")
+				i += 2
+			case strings.HasPrefix(pattern[i:], "**"):
+				expr.WriteString(".*")
+				i++
+			case pattern[i] == '*':
+				expr.WriteString("[^/]*")
+			case pattern[i] == '?':
+				expr.WriteString("[^/]")
+			default:
+				expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		}
+		expr.WriteString("$")
+
+		r, err := regexp.Compile(expr.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, pathPattern{regexp: r, basename: !strings.Contains(pattern, "/")})
+	}
+	return compiled, nil
+}
+
+func (p pathPattern) match(name string) bool {
+	if p.basename {
+		name = path.Base(name)
+	}
+	return p.regexp.MatchString(name)
+}
+
+// onlyExcludedPaths reports whether all files changed by the commit match one of the patterns,
+// renamed files have to match with both their old and new path
+func onlyExcludedPaths(commit *object.Commit, patterns []pathPattern) (bool, error) {
+	paths, err := changedPaths(commit)
+	if err != nil {
+		return false, fmt.Errorf("unable to read changes of commit %s: %w", commit.Hash, err)
+	}
+	if len(paths) == 0 {
+		return false, nil
+	}
+	for _, name := range paths {
+		excluded := false
+		for _, pattern := range patterns {
+			if pattern.match(name) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// changedPaths returns the paths changed by the commit compared to its first parent, both paths of moved files are returned
+func changedPaths(commit *object.Commit) ([]string, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	parentTree := &object.Tree{}
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := parentTree.Diff(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" && !slices.Contains(paths, name) {
+				paths = append(paths, name)
+			}
+		}
+	}
+	return paths, nil
+}
+
+var skipMarkerRegexp = regexp.MustCompile(`(?i)\[skip (changelog|release)\]`)
+
+// hasSkipMarker reports whether the commit message contains `[skip changelog]` or `[skip release]`
+func hasSkipMarker(msg string) bool {
+	return skipMarkerRegexp.MatchString(msg)
+}
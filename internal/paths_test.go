@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathPatterns(t *testing.T) {
+	var cases = []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"docs/**", "docs/readme.md", true},
+		{"docs/**", "docs/api/readme.md", true},
+		{"docs/**", "src/docs/readme.md", false},
+		{"docs/", "docs/api/readme.md", true},
+		{"/docs/", "docs/api/readme.md", true},
+		{".github/**", ".github/workflows/main.yml", true},
+		{"*.md", "Readme.md", true},
+		{"*.md", "docs/api/readme.md", true},
+		{"*.md", "main.go", false},
+		{"**/testdata/**", "internal/testdata/file.json", true},
+		{"**/testdata/**", "testdata/file.json", true},
+		{"internal/*.go", "internal/repo.go", true},
+		{"internal/*.go", "internal/hook/npm.go", false},
+		{"internal/?epo.go", "internal/repo.go", true},
+	}
+
+	for _, c := range cases {
+		patterns, err := compilePathPatterns([]string{c.pattern})
+		assert.NoError(t, err)
+		assert.Len(t, patterns, 1)
+		assert.Equal(t, c.match, patterns[0].match(c.path), "pattern %q, path %q", c.pattern, c.path)
+	}
+}
+
+func TestHasSkipMarker(t *testing.T) {
+	assert.True(t, hasSkipMarker("docs: typo [skip changelog]"))
+	assert.True(t, hasSkipMarker("chore: bump\n\n[Skip Release]"))
+	assert.False(t, hasSkipMarker("fix: skip changelog for docs"))
+	assert.False(t, hasSkipMarker("ci: [skip ci]"))
+}
+
+func TestReadRepositoryExcludePaths(t *testing.T) {
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+
+	change := func(msg string, files ...string) {
+		for _, name := range files {
+			f, err := mockWt.Filesystem.Create(name)
+			assert.NoError(t, err)
+			f.Write([]byte(msg))
+			f.Close()
+			_, err = mockWt.Add(name)
+			assert.NoError(t, err)
+		}
+		_, err := mockWt.Commit(msg, &git.CommitOptions{})
+		assert.NoError(t, err)
+	}
+
+	base := testCommit("feat: base")
+	mockRepo.CreateTag("v1.0.0", base, nil)
+	change("feat: docs only", "docs/readme.md", "docs/api/index.md")
+	change("fix: workflow", ".github/workflows/main.yml")
+	change("fix: code and docs", "docs/readme.md", "main.go")
+	testCommit("fix: skipped [skip changelog]")
+
+	repository, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Len(t, repository.Features, 1)
+	assert.Len(t, repository.fixes, 2)
+
+	repository, err = ReadRepository(mockRepo, Options{ExcludePaths: []string{"docs/**", ".github/"}})
+	assert.NoError(t, err)
+	assert.Empty(t, repository.Features)
+	assert.Len(t, repository.fixes, 1)
+	assert.Equal(t, "v1.0.1", repository.Version())
+
+	testCommit("feat: skipped feature [skip release]")
+	repository, err = ReadRepository(mockRepo, Options{ExcludePaths: []string{"docs/**", ".github/"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.1", repository.Version())
+
+	// a file moved out of the excluded paths changes the code
+	assert.NoError(t, mockWt.Filesystem.Rename("docs/api/index.md", "src/index.md"))
+	_, err = mockWt.Add("docs/api/index.md")
+	assert.NoError(t, err)
+	_, err = mockWt.Add("src/index.md")
+	assert.NoError(t, err)
+	moved, err := mockWt.Commit("feat: move api docs to src", &git.CommitOptions{})
+	assert.NoError(t, err)
+	commit, err := mockRepo.CommitObject(moved)
+	assert.NoError(t, err)
+	paths, err := changedPaths(commit)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"docs/api/index.md", "src/index.md"}, paths)
+
+	repository, err = ReadRepository(mockRepo, Options{ExcludePaths: []string{"docs/**", ".github/"}})
+	assert.NoError(t, err)
+	assert.Len(t, repository.Features, 1)
+
+	repository, err = ReadRepository(mockRepo, Options{ExcludePaths: []string{"*.md"}})
+	assert.NoError(t, err)
+	assert.Empty(t, repository.Features)
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// ReleaseBranchEdited reports whether the release branch of the open release merge request contains manual edits,
+// they are kept instead of being force-pushed. The edits are listed in a comment on the merge request once per tip
+// of the release branch. Without open merge request the branch is not preserved, e.g. if it was left behind after a squash merge.
+func ReleaseBranchEdited(repo *git.Repository, backend Backend, branch string, head plumbing.Hash, authorEmail, version string) (bool, error) {
+	number, err := backend.ReleaseMergeRequest()
+	if err != nil {
+		return false, err
+	}
+	if number == 0 {
+		return false, nil
+	}
+	edits, tip, err := ManualEdits(repo, branch, head, authorEmail, backend)
+	if err != nil {
+		return false, err
+	}
+	if len(edits) == 0 {
+		return false, nil
+	}
+
+	log.Printf("[semanticore] release branch %s contains %d commits not authored by %s, skipping the update to %s", branch, len(edits), authorEmail, version)
+	comments, err := backend.MergeRequestComments(number)
+	if err != nil {
+		return false, err
+	}
+	for _, comment := range comments {
+		if strings.Contains(comment, manualEditsMarker(tip)) {
+			log.Printf("[semanticore] manual edits up to %s are already reported", tip)
+			return true, nil
+		}
+	}
+	return true, backend.CommentMergeRequest(number, formatManualEdits(edits, tip, version))
+}
+
+// ManualEdits fetches the release branch from origin and returns its commits which are not reachable from head
+// and not authored by semanticore, e.g. changelog edits of maintainers. Merge commits like Github's "Update branch" are ignored.
+// It also returns the tip of the release branch, both are empty if the release branch does not exist.
+func ManualEdits(repo *git.Repository, branch string, head plumbing.Hash, authorEmail string, auth transport.AuthMethod) ([]*object.Commit, plumbing.Hash, error) {
+	remoteRef := plumbing.NewRemoteReferenceName("origin", branch)
+	err := repo.Fetch(&git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", plumbing.NewBranchReferenceName(branch), remoteRef))},
+		Auth:       auth,
+	})
+	if errors.Is(err, git.NoMatchingRefSpecError{}) {
+		return nil, plumbing.ZeroHash, nil
+	}
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, plumbing.ZeroHash, fmt.Errorf("unable to fetch release branch %s: %w", branch, err)
+	}
+
+	ref, err := repo.Reference(remoteRef, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("unable to read release branch %s: %w", branch, err)
+	}
+	tip, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("unable to read release branch %s: %w", branch, err)
+	}
+	headCommit, err := repo.CommitObject(head)
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("unable to read head commit: %w", err)
+	}
+	shallow, err := shallowCommits(repo)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	commits, err := commitRange(headCommit, tip, missingParents(repo, shallow))
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+	var edits []*object.Commit
+	for _, commit := range commits {
+		if len(commit.ParentHashes) > 1 || strings.EqualFold(commit.Author.Email, authorEmail) {
+			continue
+		}
+		edits = append(edits, commit)
+	}
+	return edits, tip.Hash, nil
+}
+
+// manualEditsMarker identifies the comment about the manual edits up to the tip of the release branch,
+// the comment is only posted again if the release branch changed
+func manualEditsMarker(tip plumbing.Hash) string {
+	return fmt.Sprintf("<!-- semanticore:release-branch %s -->", tip)
+}
+
+// formatManualEdits renders the comment posted on the release merge request if its branch is not updated
+func formatManualEdits(edits []*object.Commit, tip plumbing.Hash, version string) string {
+	var b strings.Builder
+	b.WriteString(manualEditsMarker(tip) + "\n")
+	fmt.Fprintf(&b, "## ⚠️ Release branch not updated\n\nThe release branch contains commits which were not created by semanticore. They are preserved, so the release is not updated to %s:\n\n", version)
+	for _, commit := range edits {
+		fmt.Fprintf(&b, "- `%s` %s by %s\n", commit.Hash.String()[:8], subject(commit.Message), commit.Author.Email)
+	}
+	b.WriteString("\nMerge this merge request to release the edited changelog, or delete the release branch to let semanticore recreate it.\n")
+	return b.String()
+}
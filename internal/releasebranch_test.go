@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManualEdits(t *testing.T) {
+	remoteDir := t.TempDir()
+	_, err := git.PlainInit(remoteDir, true)
+	assert.NoError(t, err)
+
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+	head := testCommit("feat: csv export")
+	_, err = mockRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}})
+	assert.NoError(t, err)
+	assert.NoError(t, mockRepo.Push(&git.PushOptions{RemoteName: "origin"}))
+	push := func(hash plumbing.Hash) {
+		assert.NoError(t, mockRepo.Push(&git.PushOptions{RemoteName: "origin", Force: true, RefSpecs: []config.RefSpec{config.RefSpec(hash.String() + ":refs/heads/semanticore/release")}}))
+	}
+	commit := func(msg, email string, parents ...plumbing.Hash) plumbing.Hash {
+		author := &object.Signature{Name: email, Email: email, When: time.Now()}
+		hash, err := mockWt.Commit(msg, &git.CommitOptions{Author: author, Parents: parents, AllowEmptyCommits: true})
+		assert.NoError(t, err)
+		return hash
+	}
+
+	edits, tip, err := ManualEdits(mockRepo, "semanticore/release", head, "semanticore@aoe.com", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, edits)
+	assert.Equal(t, plumbing.ZeroHash, tip)
+
+	release := commit("Release v0.1.0", "semanticore@aoe.com", head)
+	push(release)
+	edits, _, err = ManualEdits(mockRepo, "semanticore/release", head, "Semanticore@aoe.com", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, edits)
+
+	update := commit("fix: update from main", "testing@example.com", head)
+	merge := commit("Merge branch 'main' into semanticore/release", "testing@example.com", release, update)
+	edit := commit("docs: reword changelog", "testing@example.com", merge)
+	push(edit)
+	edits, tip, err = ManualEdits(mockRepo, "semanticore/release", update, "semanticore@aoe.com", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, edit, tip)
+	if assert.Len(t, edits, 1) {
+		assert.Equal(t, edit, edits[0].Hash)
+		comment := formatManualEdits(edits, tip, "v0.1.1")
+		assert.Contains(t, comment, "- `"+edit.String()[:8]+"` docs: reword changelog by testing@example.com\n")
+		assert.Contains(t, comment, manualEditsMarker(edit))
+		assert.NotContains(t, comment, manualEditsMarker(merge))
+	}
+
+	// a release commit based on an older head is still recognised
+	push(commit("Release v0.1.1", "semanticore@aoe.com", head))
+	edits, _, err = ManualEdits(mockRepo, "semanticore/release", update, "semanticore@aoe.com", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, edits)
+}
+
+func TestReleaseBranchEdited(t *testing.T) {
+	remoteDir := t.TempDir()
+	_, err := git.PlainInit(remoteDir, true)
+	assert.NoError(t, err)
+
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+	head := testCommit("feat: csv export")
+	_, err = mockRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}})
+	assert.NoError(t, err)
+	assert.NoError(t, mockRepo.Push(&git.PushOptions{RemoteName: "origin"}))
+	push := func(hash plumbing.Hash) {
+		assert.NoError(t, mockRepo.Push(&git.PushOptions{RemoteName: "origin", Force: true, RefSpecs: []config.RefSpec{config.RefSpec(hash.String() + ":refs/heads/semanticore/release")}}))
+	}
+	commit := func(msg, email string, parents ...plumbing.Hash) plumbing.Hash {
+		author := &object.Signature{Name: email, Email: email, When: time.Now()}
+		hash, err := mockWt.Commit(msg, &git.CommitOptions{Author: author, Parents: parents, AllowEmptyCommits: true})
+		assert.NoError(t, err)
+		return hash
+	}
+
+	backend := &testBackend{releaseMergeRequest: 3}
+	edited, err := ReleaseBranchEdited(mockRepo, backend, "semanticore/release", head, "semanticore@aoe.com", "v0.1.0")
+	assert.NoError(t, err)
+	assert.False(t, edited)
+
+	release := commit("Release v0.1.0", "semanticore@aoe.com", head)
+	edit := commit("docs: reword changelog", "testing@example.com", release)
+	push(edit)
+	for i := 0; i < 2; i++ {
+		edited, err = ReleaseBranchEdited(mockRepo, backend, "semanticore/release", head, "semanticore@aoe.com", "v0.1.0")
+		assert.NoError(t, err)
+		assert.True(t, edited)
+	}
+	if assert.Len(t, backend.comments, 1, "the edits are only reported once") {
+		assert.Contains(t, backend.comments[0], manualEditsMarker(edit))
+	}
+
+	second := commit("docs: fix typo", "testing@example.com", edit)
+	push(second)
+	edited, err = ReleaseBranchEdited(mockRepo, backend, "semanticore/release", head, "semanticore@aoe.com", "v0.1.0")
+	assert.NoError(t, err)
+	assert.True(t, edited)
+	if assert.Len(t, backend.comments, 2) {
+		assert.Contains(t, backend.comments[1], manualEditsMarker(second))
+	}
+
+	// the merge request was squash merged and the release branch was left behind
+	squash := commit("Release v0.1.0 (#3)", "testing@example.com", head)
+	edits, _, err := ManualEdits(mockRepo, "semanticore/release", squash, "semanticore@aoe.com", nil)
+	assert.NoError(t, err)
+	assert.Len(t, edits, 2)
+	backend.releaseMergeRequest = 0
+	edited, err = ReleaseBranchEdited(mockRepo, backend, "semanticore/release", squash, "semanticore@aoe.com", "v0.1.1")
+	assert.NoError(t, err)
+	assert.False(t, edited)
+	assert.Len(t, backend.comments, 2)
+}
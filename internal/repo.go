@@ -1,10 +1,10 @@
 package internal
 
 import (
-	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -12,14 +12,15 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/storer"
 )
 
 type Repository struct {
 	Major, Minor, Patch int
 	VPrefix             string
+	TagPrefix           string
 	Latest              string
 
+	breaking    []string
 	fixes       []string
 	Features    []string
 	other       []string
@@ -33,16 +34,213 @@ type Repository struct {
 	releaseDate time.Time
 	Breaking    bool
 	Details     []string
+	Bump        Bump
+	scopes      map[CommitType][]string
+	// OverriddenBy names the source of a forced version: either a commit hash with a `Release-As` footer or the version flag
+	OverriddenBy string
+	// Contributors are the authors and co-authors of the release, only collected with the Contributors option
+	Contributors []Contributor
+	// Unverified are the commits since the latest tag without a valid signature, only checked with the TrustedKeys option
+	Unverified []UnverifiedCommit
 
 	changelog string
 
+	// tagged is the latest version tag, it is nil if nothing was tagged yet
+	tagged *plumbing.Reference
+
 	unreleased          string
 	unreleasedChangelog string
 }
 
-func ReadRepository(repo *git.Repository, createMajor bool) (*Repository, error) {
+// Bump describes which part of the version is increased by a release
+type Bump string
+
+const (
+	BumpMajor Bump = "major"
+	BumpMinor Bump = "minor"
+	BumpPatch Bump = "patch"
+)
+
+// Options configure how the next version is derived from the commit history
+type Options struct {
+	// CreateMajor allows breaking changes to bump the major version
+	CreateMajor bool
+	// SemverZero applies the semver rules for initial development while the major version is 0:
+	// breaking changes bump the minor version and features the patch version
+	SemverZero bool
+	// SemverZeroFeatureMinor keeps bumping the minor version for features while SemverZero is active
+	SemverZeroFeatureMinor bool
+	// Stable graduates a 0.x version to 1.0.0 with the next release
+	Stable bool
+	// Version forces the next version, it takes precedence over `Release-As` commit footers
+	Version string
+	// TagPrefix is the prefix of version tags, e.g. `app-` for tags like `app-v1.2.3`
+	TagPrefix string
+	// Links are used to link issue references in the changelog
+	Links Links
+	// SplitSquash creates a changelog entry for every conventional commit listed in the body of a squash commit
+	SplitSquash bool
+	// CommitSource selects the commits which are part of the changelog
+	CommitSource CommitSource
+	// Backend is used to look up information not available in the repository, it is optional
+	Backend Backend
+	// MergeRequestLinks adds the merge request and its author to every changelog entry, it requires a Backend
+	MergeRequestLinks bool
+	// ExcludePaths are globs of paths, commits only changing matching paths are omitted
+	ExcludePaths []string
+	// Scopes lists the known scopes, see UnknownScopes
+	Scopes []string
+	// UnknownScopes handles scopes not listed in Scopes: ignore them (empty), `warn` or `fail`
+	UnknownScopes string
+	// ScopeAliases normalises scopes, e.g. `dependencies` to `deps`
+	ScopeAliases map[string]string
+	// GroupByScope renders entries below a sub-heading per scope instead of prefixing them with the scope
+	GroupByScope bool
+	// Gitmoji parses commits starting with a gitmoji like `✨ add export`, see ParseGitmojiCommit
+	Gitmoji bool
+	// Contributors adds a section listing the authors and co-authors of the release, `.mailmap` is applied
+	Contributors bool
+	// ExcludeContributors lists names or emails omitted from the contributors, e.g. of the semanticore author
+	ExcludeContributors []string
+	// ContributorHandles looks up the forge usernames of the contributors, it requires a Backend
+	ContributorHandles bool
+	// TrustedKeys is an armored PGP keyring, commits of the release are verified against it if set
+	TrustedKeys string
+	// RequireSignatures fails if a commit of the release is not signed by a trusted key
+	RequireSignatures bool
+	// SignatureTime checks the trusted keys at the creation time of each signature instead of now, so keys which expired
+	// since then stay valid for older commits. The signer sets the creation time, an expired key can still sign backdated commits.
+	SignatureTime bool
+	// ReleaseCommitTemplate detects release commits, DefaultReleaseCommitTemplate is used if empty
+	ReleaseCommitTemplate string
+}
+
+// CommitSource is a strategy to select the commits of a release
+type CommitSource string
+
+const (
+	// CommitSourceAll uses all commits except merge commits
+	CommitSourceAll CommitSource = "all"
+	// CommitSourceFirstParent uses the first-parent history, merge commits are represented by their pull request title
+	CommitSourceFirstParent CommitSource = "first-parent"
+	// CommitSourceSquash uses the first-parent history without merge commits
+	CommitSourceSquash CommitSource = "squash"
+)
+
+// firstParents filters the logs to the commits on the first-parent chain starting at head
+func firstParents(head *object.Commit, logs []*object.Commit) []*object.Commit {
+	byHash := make(map[plumbing.Hash]*object.Commit, len(logs))
+	for _, c := range logs {
+		byHash[c.Hash] = c
+	}
+
+	var chain []*object.Commit
+	for c, ok := byHash[head.Hash]; ok; {
+		chain = append(chain, c)
+		if len(c.ParentHashes) == 0 {
+			break
+		}
+		c, ok = byHash[c.ParentHashes[0]]
+	}
+	return chain
+}
+
+// releaseChangelog returns the latest version section of the changelog.md in a release commit
+func releaseChangelog(commit *object.Commit) string {
+	var changelog string
+	fi, err := commit.Files()
+	if err != nil {
+		return ""
+	}
+	fi.ForEach(func(f *object.File) error {
+		if strings.ToLower(f.Name) == "changelog.md" {
+			c, _ := f.Contents()
+			if sections := strings.Split(c, "## Version "); len(sections) > 1 {
+				changelog = strings.TrimSpace("## Version " + sections[1])
+			}
+		}
+		return nil
+	})
+	return changelog
+}
+
+// commitRange returns the commits reachable from head but not from ancestor like `git log ancestor..head`,
+// the history of ancestor is walked once instead of checking every commit for ancestry
+func commitRange(ancestor, head *object.Commit, missing []plumbing.Hash) ([]*object.Commit, error) {
+	reachable := make(map[plumbing.Hash]bool)
+	if ancestor != nil {
+		err := object.NewCommitPreorderIter(ancestor, nil, missing).ForEach(func(c *object.Commit) error {
+			reachable[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to read history of %s: %w", ancestor.Hash, err)
+		}
+	}
+
+	var logs []*object.Commit
+	err := object.NewCommitIterBSF(head, reachable, missing).ForEach(func(c *object.Commit) error {
+		logs = append(logs, c)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read history of %s: %w", head.Hash, err)
+	}
+	return logs, nil
+}
+
+// mergeMessage returns the pull request title of a merge commit followed by the body of the merge commit,
+// the title is looked up through mergeRequests if the message lacks it. It is empty if there is no title.
+func mergeMessage(msg string, mergeRequests *mergeRequestCache) string {
+	title, ref, number, ok := ParseMergeCommit(msg)
+	if !ok {
+		return ""
+	}
+	if title == "" && number > 0 && mergeRequests != nil {
+		if mr := mergeRequests.lookupNumber(number); mr != nil {
+			title = mr.Title
+		}
+	}
+	if title == "" {
+		return ""
+	}
+	if ref != "" && !strings.Contains(title, ref) {
+		title = fmt.Sprintf("%s (%s)", title, ref)
+	}
+	if body := mergeBody(msg); body != "" {
+		return title + "\n\n" + body
+	}
+	return title
+}
+
+// prefetchMergeTitles looks up the titles of the merge requests missing in merge commit messages concurrently
+func prefetchMergeTitles(commits []*object.Commit, backend Backend) *mergeRequestCache {
+	if backend == nil {
+		return nil
+	}
+	var numbers []int
+	for _, commit := range commits {
+		if len(commit.ParentHashes) < 2 {
+			continue
+		}
+		if title, _, number, ok := ParseMergeCommit(commit.Message); ok && title == "" && number > 0 {
+			numbers = append(numbers, number)
+		}
+	}
+	mergeRequests := newMergeRequestCache(backend)
+	mergeRequests.resolveNumbers(numbers)
+	return mergeRequests
+}
+
+func ReadRepository(repo *git.Repository, options Options) (*Repository, error) {
+	excludePaths, err := compilePathPatterns(options.ExcludePaths)
+	if err != nil {
+		return nil, err
+	}
+
 	repository := &Repository{
-		VPrefix: "v",
+		VPrefix:   "v",
+		TagPrefix: options.TagPrefix,
 	}
 
 	tags := make(map[string][]*plumbing.Reference)
@@ -64,38 +262,6 @@ func ReadRepository(repo *git.Repository, createMajor bool) (*Repository, error)
 		return nil, fmt.Errorf("unable to iterate git tags: %w", err)
 	}
 
-	glog, err := repo.Log(&git.LogOptions{
-		Order: git.LogOrderCommitterTime,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("unable to read repository log: %w", err)
-	}
-
-	vregex := regexp.MustCompile(`(v?)(\d+).(\d+).(\d+)`)
-	var ancestor *object.Commit
-	glog.ForEach(func(c *object.Commit) error {
-		if tags, ok := tags[c.Hash.String()]; ok {
-			for _, tag := range tags {
-				match := vregex.FindStringSubmatch(tag.Name().String())
-				if match == nil {
-					continue
-				}
-				tagMajor, _ := strconv.Atoi(match[2])
-				tagMinor, _ := strconv.Atoi(match[3])
-				tagPatch, _ := strconv.Atoi(match[4])
-				if tagMajor > repository.Major || (tagMajor == repository.Major && tagMinor > repository.Minor) || (tagMajor == repository.Major && tagMinor == repository.Minor && tagPatch > repository.Patch) {
-					repository.Major = tagMajor
-					repository.Minor = tagMinor
-					repository.Patch = tagPatch
-					repository.VPrefix = match[1]
-					ancestor = c
-					return errors.New("done")
-				}
-			}
-		}
-		return nil
-	})
-
 	head, err := repo.Head()
 	if err != nil {
 		return nil, fmt.Errorf("repo.Head() failed :%w", err)
@@ -106,99 +272,150 @@ func ReadRepository(repo *git.Repository, createMajor bool) (*Repository, error)
 		return nil, fmt.Errorf("unable to read head commit :%w", err)
 	}
 
-	var ignore []plumbing.Hash
-	var seen map[plumbing.Hash]bool
-	if ancestor != nil {
-		ignore = append(ignore, ancestor.Hash)
-		seen = map[plumbing.Hash]bool{ancestor.Hash: true}
+	shallow, err := shallowCommits(repo)
+	if err != nil {
+		return nil, err
 	}
+	// the parents of shallow commits are not in the clone, go-git fails to load them
+	missing := missingParents(repo, shallow)
 
-	var logs []*object.Commit
-	object.NewCommitIterBSF(headCommit, seen, ignore).ForEach(func(c *object.Commit) error {
-		if a, _ := c.IsAncestor(ancestor); a {
-			return storer.ErrStop
+	glog := object.NewCommitIterCTime(headCommit, nil, missing)
+
+	tagRegex := regexp.MustCompile(`^` + regexp.QuoteMeta(options.TagPrefix) + `(v?)(\d+)\.(\d+)\.(\d+)$`)
+	var ancestor *object.Commit
+	err = glog.ForEach(func(c *object.Commit) error {
+		for _, tag := range tags[c.Hash.String()] {
+			match := tagRegex.FindStringSubmatch(tag.Name().Short())
+			if match == nil {
+				continue
+			}
+			tagMajor, _ := strconv.Atoi(match[2])
+			tagMinor, _ := strconv.Atoi(match[3])
+			tagPatch, _ := strconv.Atoi(match[4])
+			if compareVersions(tagMajor, tagMinor, tagPatch, repository.Major, repository.Minor, repository.Patch) > 0 {
+				repository.Major = tagMajor
+				repository.Minor = tagMinor
+				repository.Patch = tagPatch
+				repository.VPrefix = match[1]
+				ancestor = c
+				repository.tagged = tag
+			}
 		}
-		logs = append(logs, c)
 		return nil
 	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate repository log: %w", err)
+	}
 
-	repository.Latest = fmt.Sprintf("%s%d.%d.%d", repository.VPrefix, repository.Major, repository.Minor, repository.Patch)
-	log.Printf("[semanticore] Current version: %s", repository.Latest)
+	logs, err := commitRange(ancestor, headCommit, missing)
+	if err != nil {
+		return nil, err
+	}
+	// all untagged commits are verified, including merged branches and commits of a pending release commit
+	if err := checkSignatures(repository, logs, options); err != nil {
+		return nil, err
+	}
 
-	reverst := regexp.MustCompile(`This reverts commit ([a-zA-Z0-9]+)`)
-	_ = reverst
+	if options.CommitSource == CommitSourceFirstParent || options.CommitSource == CommitSourceSquash {
+		logs = firstParents(headCommit, logs)
+	}
+	if err := checkShallowHistory(logs, shallow); err != nil {
+		return nil, err
+	}
 
-	reverted := make(map[string]struct{})
+	repository.Latest = repository.Tag()
+	log.Printf("[semanticore] Current version: %s", repository.Latest)
+	// previous is the tag of the last release, it is empty if nothing was released yet
+	var previous string
+	if ancestor != nil {
+		previous = repository.Latest
+	}
+
+	// reverts are only paired within the unreleased commits
+	unreleased := logs
+	for i, commit := range logs {
+		if _, major, minor, patch := DetectReleaseCommit(strings.TrimSpace(commit.Message), len(commit.ParentHashes) > 1, options.ReleaseCommitTemplate); major+minor+patch > 0 {
+			unreleased = logs[:i]
+			break
+		}
+	}
+	reverted := pairReverts(unreleased)
 	updates := 0
+	var entries []changelogEntry
+	forced, forcedBy := options.Version, ""
+	if forced != "" {
+		forcedBy = "the version flag"
+	}
+
+	var mergeTitles *mergeRequestCache
+	if options.CommitSource == CommitSourceFirstParent {
+		mergeTitles = prefetchMergeTitles(unreleased, options.Backend)
+	}
 
 	for _, commit := range logs {
-		if _, ok := reverted[commit.Hash.String()]; ok {
+		if reverted[commit.Hash] {
 			continue
 		}
 		msg := strings.TrimSpace(commit.Message)
-		if match := reverst.FindStringSubmatch(msg); match != nil {
-			reverted[match[1]] = struct{}{}
-			continue
-		}
 
-		if newVprefix, newMajor, newMinor, newPatch := DetectReleaseCommit(msg, len(commit.ParentHashes) > 1); newMajor+newMinor+newPatch > 0 {
+		if newVprefix, newMajor, newMinor, newPatch := DetectReleaseCommit(msg, len(commit.ParentHashes) > 1, options.ReleaseCommitTemplate); newMajor+newMinor+newPatch > 0 {
 			repository.Major = newMajor
 			repository.Minor = newMinor
 			repository.Patch = newPatch
 			repository.VPrefix = newVprefix
-			repository.Latest = fmt.Sprintf("%s%d.%d.%d", repository.VPrefix, repository.Major, repository.Minor, repository.Patch)
+			repository.Latest = repository.Tag()
+			previous = repository.Latest
 			log.Printf("[semanticore] found version %s at %s: %q", repository.Latest, commit.Hash, msg)
 
 			repository.unreleased = commit.Hash.String()
 
-			fi, err := commit.Files()
-			if err == nil {
-				fi.ForEach(func(f *object.File) error {
-					if strings.ToLower(f.Name) == "changelog.md" {
-						c, _ := f.Contents()
-						repository.unreleasedChangelog = "## Version " + strings.Split(c, "## Version ")[1]
-						repository.unreleasedChangelog = strings.TrimSpace(repository.unreleasedChangelog)
-					}
-					return nil
-				})
-			}
+			repository.unreleasedChangelog = releaseChangelog(commit)
 
 			break
 		}
 
 		if len(commit.ParentHashes) > 1 {
+			if options.CommitSource != CommitSourceFirstParent {
+				continue
+			}
+			merged := mergeMessage(msg, mergeTitles)
+			if merged == "" {
+				continue
+			}
+			msg = merged
+		}
+		if hasSkipMarker(msg) {
 			continue
 		}
+		if len(excludePaths) > 0 {
+			excluded, err := onlyExcludedPaths(commit, excludePaths)
+			if err != nil {
+				return nil, err
+			}
+			if excluded {
+				continue
+			}
+		}
+		if forced == "" {
+			if releaseAs := DetectReleaseAs(msg); releaseAs != "" {
+				forced, forcedBy = releaseAs, "commit "+commit.Hash.String()[:8]
+			}
+		}
 		if commit.Committer.When.After(repository.releaseDate) {
 			repository.releaseDate = commit.Committer.When
 		}
-		typ, scope, msg, major := ParseCommitMessage(msg)
-		repository.Breaking = repository.Breaking || major
-		line := fmt.Sprintf("%s (%s)", msg, commit.Hash.String()[:8])
-		if scope != "" {
-			line = fmt.Sprintf("**%s:** %s (%s)", scope, msg, commit.Hash.String()[:8])
-		}
-		switch typ {
-		case TypeFeat:
-			repository.Features = append(repository.Features, line)
-		case TypeFix:
-			repository.fixes = append(repository.fixes, line)
-		case TypeTest:
-			repository.tests = append(repository.tests, line)
-		case TypeChore:
-			repository.chores = append(repository.chores, line)
-		case TypeOps:
-			repository.ops = append(repository.ops, line)
-		case TypeDocs:
-			repository.docs = append(repository.docs, line)
-		case TypePerf:
-			repository.perf = append(repository.perf, line)
-		case TypeRefactor:
-			repository.refactor = append(repository.refactor, line)
-		case TypeSecurity:
-			repository.security = append(repository.security, line)
-		default:
-			repository.other = append(repository.other, line)
+		parsed := []ConventionalCommit{ParseCommit(msg)}
+		if options.Gitmoji {
+			parsed[0] = ParseGitmojiCommit(msg)
+		}
+		if options.SplitSquash {
+			if squashed := ParseSquashCommit(msg, options.Gitmoji); len(squashed) > 0 {
+				parsed = squashed
+			}
+		}
+		for _, p := range parsed {
+			p.Scope = normalizeScope(p.Scope, options.ScopeAliases)
+			entries = append(entries, changelogEntry{ConventionalCommit: p, commit: commit})
 		}
 		updates++
 	}
@@ -207,15 +424,64 @@ func ReadRepository(repo *git.Repository, createMajor bool) (*Repository, error)
 		return repository, nil
 	}
 
-	if repository.Breaking && createMajor {
-		repository.Major++
-		repository.Minor = 0
-		repository.Patch = 0
-	} else if len(repository.Features) > 0 {
-		repository.Minor++
-		repository.Patch = 0
+	if err := checkScopes(entries, options); err != nil {
+		return nil, err
+	}
+
+	if options.MergeRequestLinks && options.Backend != nil {
+		var shas []string
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			if sha := entry.commit.Hash.String(); !seen[sha] {
+				seen[sha] = true
+				shas = append(shas, sha)
+			}
+		}
+		mergeRequests := newMergeRequestCache(options.Backend)
+		mergeRequests.resolve(shas)
+		for i := range entries {
+			entries[i].mergeRequest = mergeRequests.lookup(entries[i].commit.Hash.String())
+		}
+	}
+
+	if options.Contributors {
+		var commits []*object.Commit
+		seen := make(map[plumbing.Hash]bool)
+		for _, entry := range entries {
+			if !seen[entry.commit.Hash] {
+				seen[entry.commit.Hash] = true
+				commits = append(commits, entry.commit)
+			}
+		}
+		repository.Contributors = collectContributors(commits, readMailmap(headCommit), options.ExcludeContributors)
+		if options.ContributorHandles {
+			resolveHandles(repository.Contributors, options.Backend)
+		}
+	}
+
+	for _, entry := range entries {
+		repository.add(entry, options)
+	}
+
+	if forced != "" {
+		if err := repository.forceVersion(forced); err != nil {
+			return nil, fmt.Errorf("unable to force version from %s: %w", forcedBy, err)
+		}
+		repository.OverriddenBy = forcedBy
+		log.Printf("[semanticore] version %s forced by %s", repository.Version(), forcedBy)
 	} else {
-		repository.Patch++
+		repository.Bump = repository.nextBump(options)
+		switch repository.Bump {
+		case BumpMajor:
+			repository.Major++
+			repository.Minor = 0
+			repository.Patch = 0
+		case BumpMinor:
+			repository.Minor++
+			repository.Patch = 0
+		default:
+			repository.Patch++
+		}
 	}
 
 	repository.changelog = fmt.Sprintf("# Changelog\n\n## Version %s%d.%d.%d (%s)\n\n", repository.VPrefix, repository.Major, repository.Minor, repository.Patch, repository.releaseDate.Format("2006-01-02"))
@@ -224,17 +490,19 @@ func ReadRepository(repo *git.Repository, createMajor bool) (*Repository, error)
 		title  string
 		logs   []string
 		detail string
+		typ    CommitType
 	}{
-		{"### Features", repository.Features, "🆕 feature"},
-		{"### Security Fixes", repository.security, "🚨 security"},
-		{"### Fixes", repository.fixes, "👾 fix"},
-		{"### Tests", repository.tests, "🛡 test"},
-		{"### Refactoring", repository.refactor, "🔁 refactor"},
-		{"### Ops and CI/CD", repository.ops, "🤖 devops"},
-		{"### Documentation", repository.docs, "📚 doc"},
-		{"### Performance", repository.perf, "⚡️ performance"},
-		{"### Chores and tidying", repository.chores, "🧹 chore"},
-		{"### Other", repository.other, "📝 other"},
+		{"### ⚠️ Breaking Changes", repository.breaking, "", ""},
+		{"### Features", repository.Features, "🆕 feature", TypeFeat},
+		{"### Security Fixes", repository.security, "🚨 security", TypeSecurity},
+		{"### Fixes", repository.fixes, "👾 fix", TypeFix},
+		{"### Tests", repository.tests, "🛡 test", TypeTest},
+		{"### Refactoring", repository.refactor, "🔁 refactor", TypeRefactor},
+		{"### Ops and CI/CD", repository.ops, "🤖 devops", TypeOps},
+		{"### Documentation", repository.docs, "📚 doc", TypeDocs},
+		{"### Performance", repository.perf, "⚡️ performance", TypePerf},
+		{"### Chores and tidying", repository.chores, "🧹 chore", TypeChore},
+		{"### Other", repository.other, "📝 other", TypeOther},
 	}
 
 	for _, log := range changelogentries {
@@ -243,17 +511,182 @@ func ReadRepository(repo *git.Repository, createMajor bool) (*Repository, error)
 		}
 		repository.changelog += fmt.Sprintln(log.title)
 		repository.changelog += fmt.Sprintln()
-		for _, line := range log.logs {
-			repository.changelog += fmt.Sprintln("- " + line)
+		if scopes, ok := repository.scopes[log.typ]; ok && log.typ != "" {
+			repository.changelog += groupByScope(log.logs, scopes)
+		} else {
+			for _, line := range log.logs {
+				repository.changelog += fmt.Sprintln("- " + line)
+			}
 		}
 		repository.changelog += fmt.Sprintln()
-		repository.Details = append(repository.Details, fmt.Sprintf("%d %s", len(log.logs), log.detail))
+		if log.detail != "" {
+			repository.Details = append(repository.Details, fmt.Sprintf("%d %s", len(log.logs), log.detail))
+		}
+	}
+	repository.changelog += formatContributors(repository.Contributors)
+	if compare := options.Links.compare(previous, repository.Tag()); compare != "" {
+		repository.changelog += fmt.Sprintf("[Full diff](%s)\n\n", compare)
 	}
 
 	return repository, nil
 }
 
+type changelogEntry struct {
+	ConventionalCommit
+	commit       *object.Commit
+	mergeRequest *MergeRequestInfo
+}
+
+func (repository *Repository) add(entry changelogEntry, options Options) {
+	repository.Breaking = repository.Breaking || entry.Breaking
+	line := entry.Description
+	if mr := entry.mergeRequest; mr != nil {
+		// squash commits usually already mention the merge request in the title
+		line = strings.TrimSpace(strings.TrimSuffix(line, fmt.Sprintf("(%s)", mr.Ref)))
+	}
+	if refs := entry.IssueRefs(); len(refs) > 0 {
+		for i, ref := range refs {
+			refs[i] = options.Links.issue(ref)
+		}
+		line = fmt.Sprintf("%s (%s)", line, strings.Join(refs, ", "))
+	}
+	if mr := entry.mergeRequest; mr != nil {
+		reference := fmt.Sprintf("[%s](%s)", mr.Ref, mr.URL)
+		if mr.Author != "" {
+			reference += " by @" + mr.Author
+		}
+		line = fmt.Sprintf("%s (%s)", line, reference)
+	}
+	line = fmt.Sprintf("%s (%s)", line, options.Links.commit(entry.commit.Hash.String()))
+	scopedLine := line
+	if entry.Scope != "" {
+		scopedLine = fmt.Sprintf("**%s:** %s", entry.Scope, line)
+	}
+	if options.GroupByScope {
+		if repository.scopes == nil {
+			repository.scopes = make(map[CommitType][]string)
+		}
+		repository.scopes[entry.Type] = append(repository.scopes[entry.Type], entry.Scope)
+	} else {
+		line = scopedLine
+	}
+	if entry.Breaking {
+		breaking := scopedLine
+		if entry.BreakingChange != "" {
+			breaking += "\n" + indent(entry.BreakingChange, "  ")
+		}
+		repository.breaking = append(repository.breaking, breaking)
+	}
+	switch entry.Type {
+	case TypeFeat:
+		repository.Features = append(repository.Features, line)
+	case TypeFix:
+		repository.fixes = append(repository.fixes, line)
+	case TypeTest:
+		repository.tests = append(repository.tests, line)
+	case TypeChore:
+		repository.chores = append(repository.chores, line)
+	case TypeOps:
+		repository.ops = append(repository.ops, line)
+	case TypeDocs:
+		repository.docs = append(repository.docs, line)
+	case TypePerf:
+		repository.perf = append(repository.perf, line)
+	case TypeRefactor:
+		repository.refactor = append(repository.refactor, line)
+	case TypeSecurity:
+		repository.security = append(repository.security, line)
+	default:
+		repository.other = append(repository.other, line)
+	}
+}
+
+// groupByScope renders the lines below a sub-heading per scope, lines without scope come first
+func groupByScope(lines, scopes []string) string {
+	grouped := make(map[string][]string)
+	for i, line := range lines {
+		grouped[scopes[i]] = append(grouped[scopes[i]], line)
+	}
+	names := make([]string, 0, len(grouped))
+	for scope := range grouped {
+		names = append(names, scope)
+	}
+	sort.Strings(names)
+
+	var changelog string
+	for _, scope := range names {
+		if scope != "" {
+			if changelog != "" {
+				changelog += fmt.Sprintln()
+			}
+			changelog += fmt.Sprintf("#### %s\n\n", scope)
+		}
+		for _, line := range grouped[scope] {
+			changelog += fmt.Sprintln("- " + line)
+		}
+	}
+	return changelog
+}
+
+// indent prefixes all non-empty lines of s
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (repository *Repository) nextBump(options Options) Bump {
+	if repository.Major == 0 && options.Stable {
+		return BumpMajor
+	}
+	if repository.Major == 0 && options.SemverZero {
+		if repository.Breaking {
+			return BumpMinor
+		}
+		if len(repository.Features) > 0 && options.SemverZeroFeatureMinor {
+			return BumpMinor
+		}
+		return BumpPatch
+	}
+	if repository.Breaking && options.CreateMajor {
+		return BumpMajor
+	}
+	if len(repository.Features) > 0 {
+		return BumpMinor
+	}
+	return BumpPatch
+}
+
+func (repository *Repository) forceVersion(version string) error {
+	_, major, minor, patch, err := ParseVersion(version)
+	if err != nil {
+		return err
+	}
+	if compareVersions(major, minor, patch, repository.Major, repository.Minor, repository.Patch) <= 0 {
+		return fmt.Errorf("forced version %s is not greater than %s", version, repository.Latest)
+	}
+
+	switch {
+	case major > repository.Major:
+		repository.Bump = BumpMajor
+	case minor > repository.Minor:
+		repository.Bump = BumpMinor
+	default:
+		repository.Bump = BumpPatch
+	}
+	repository.Major, repository.Minor, repository.Patch = major, minor, patch
+	return nil
+}
+
+// Release creates the tag and release of the detected release commit, it does nothing if no release commit was detected
 func (repository *Repository) Release(backend Backend) error {
+	if repository.unreleased == "" {
+		return nil
+	}
 	if err := backend.Release(repository.Latest, repository.unreleased, repository.unreleasedChangelog); err != nil {
 		return fmt.Errorf("unable to release %s at %s: %w", repository.Latest, repository.unreleased, err)
 	}
@@ -267,3 +700,8 @@ func (repository *Repository) Changelog() string {
 func (repository *Repository) Version() string {
 	return fmt.Sprintf("%s%d.%d.%d", repository.VPrefix, repository.Major, repository.Minor, repository.Patch)
 }
+
+// Tag returns the tag name of the current version
+func (repository *Repository) Tag() string {
+	return repository.TagPrefix + repository.Version()
+}
@@ -1,20 +1,37 @@
 package internal
 
 import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 )
 
 type testBackend struct {
-	tag       string
-	ref       string
-	changelog string
+	tag           string
+	ref           string
+	changelog     string
+	mergeRequests map[int]MergeRequestInfo
+	// releaseMergeRequest is the number of the open release merge request, comments are posted on it
+	releaseMergeRequest int
+	comments            []string
+
+	mu                  sync.Mutex
+	commitMergeRequests map[string]MergeRequestInfo
+	commitLookups       int
+	users               map[string]string
 }
 
 func (*testBackend) String() string { return "testBackend" }
@@ -25,9 +42,38 @@ func (b *testBackend) Release(tag, ref, changelog string) error {
 	b.changelog = changelog
 	return nil
 }
+func (b *testBackend) HasRelease(tag string) (bool, error)                        { return b.tag == tag, nil }
 func (*testBackend) MergeRequest(target, title, description, labels string) error { return nil }
 func (*testBackend) CloseMergeRequest() error                                     { return nil }
+func (b *testBackend) ReleaseMergeRequest() (int, error)                          { return b.releaseMergeRequest, nil }
 func (*testBackend) MainBranch() (string, error)                                  { return "main", nil }
+func (*testBackend) Links() Links                                                 { return Links{} }
+func (b *testBackend) CommentMergeRequest(number int, body string) error {
+	b.comments = append(b.comments, body)
+	return nil
+}
+func (b *testBackend) MergeRequestComments(number int) ([]string, error) { return b.comments, nil }
+func (b *testBackend) FindCommitMergeRequest(sha string) (MergeRequestInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.commitLookups++
+	if mr, ok := b.commitMergeRequests[sha]; ok {
+		return mr, nil
+	}
+	return MergeRequestInfo{}, errNoMergeRequestFound
+}
+func (b *testBackend) FindUser(email string) (string, error) {
+	if handle, ok := b.users[email]; ok {
+		return handle, nil
+	}
+	return "", errNoUserFound
+}
+func (b *testBackend) FindMergeRequest(number int) (MergeRequestInfo, error) {
+	if mr, ok := b.mergeRequests[number]; ok {
+		return mr, nil
+	}
+	return MergeRequestInfo{}, errNoMergeRequestFound
+}
 
 func TestReadRepository(t *testing.T) {
 	mockRepo, err := git.Init(memory.NewStorage(), memfs.New())
@@ -48,7 +94,7 @@ func TestReadRepository(t *testing.T) {
 
 	mockWt.Checkout(&git.CheckoutOptions{Branch: "main"})
 
-	_, err = ReadRepository(mockRepo, true)
+	_, err = ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.Error(t, err)
 
 	file, err := mockWt.Filesystem.Create("test.file")
@@ -64,7 +110,7 @@ func TestReadRepository(t *testing.T) {
 
 	testCommit("test(semanticore): initial commit")
 
-	repository, err := ReadRepository(mockRepo, true)
+	repository, err := ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.NoError(t, err)
 	assert.Equal(t, "", repository.unreleased)
 	assert.Equal(t, "", repository.unreleasedChangelog)
@@ -72,17 +118,20 @@ func TestReadRepository(t *testing.T) {
 
 	vhash := testCommit("ci(semanticore): initial ci")
 	mockRepo.CreateTag("v0.0.1", vhash, nil)
-	repository, err = ReadRepository(mockRepo, true)
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.NoError(t, err)
 	assert.Equal(t, "v0.0.1", repository.Latest)
 	assert.Equal(t, "", repository.unreleased)
 
 	vhash = testCommit("ci(semanticore): initial ci")
 	mockRepo.CreateTag("v0.0.2", vhash, &git.CreateTagOptions{Message: "v0.0.2"})
-	repository, err = ReadRepository(mockRepo, true)
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.NoError(t, err)
 	assert.Equal(t, "v0.0.2", repository.Latest)
 	assert.Equal(t, "", repository.changelog)
+	noRelease := new(testBackend)
+	assert.NoError(t, repository.Release(noRelease))
+	assert.Empty(t, noRelease.tag)
 
 	cf, err := mockWt.Filesystem.Create("Changelog.md")
 	assert.NoError(t, err)
@@ -90,7 +139,7 @@ func TestReadRepository(t *testing.T) {
 	cf.Write([]byte(`## Version 1.2.3 test ## Version 1.2.3 ## Version 1.2.3`))
 	mockWt.Add("Changelog.md")
 	vhash = testCommit("Release v0.0.3")
-	repository, err = ReadRepository(mockRepo, true)
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.NoError(t, err)
 	assert.Equal(t, "v0.0.3", repository.Latest)
 	assert.Equal(t, vhash.String(), repository.unreleased)
@@ -109,7 +158,7 @@ func TestReadRepository(t *testing.T) {
 	testCommit("initial something whatever")
 	testCommit("task: initial task")
 
-	repository, err = ReadRepository(mockRepo, true)
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.NoError(t, err)
 	assert.Len(t, repository.tests, 1)
 	assert.Len(t, repository.ops, 1)
@@ -119,7 +168,7 @@ func TestReadRepository(t *testing.T) {
 
 	testCommit("feat(semanticore): initial feature")
 
-	repository, err = ReadRepository(mockRepo, true)
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.NoError(t, err)
 	assert.Len(t, repository.tests, 1)
 	assert.Len(t, repository.ops, 1)
@@ -127,9 +176,19 @@ func TestReadRepository(t *testing.T) {
 	assert.Equal(t, 1, repository.Minor)
 	assert.Equal(t, 0, repository.Patch)
 
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true, SemverZero: true})
+	assert.NoError(t, err)
+	assert.Equal(t, BumpPatch, repository.Bump)
+	assert.Equal(t, "v0.0.4", repository.Version())
+
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true, SemverZero: true, SemverZeroFeatureMinor: true})
+	assert.NoError(t, err)
+	assert.Equal(t, BumpMinor, repository.Bump)
+	assert.Equal(t, "v0.1.0", repository.Version())
+
 	testCommit("feat(semanticore): second feature")
 
-	repository, err = ReadRepository(mockRepo, true)
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.NoError(t, err)
 	assert.Len(t, repository.tests, 1)
 	assert.Len(t, repository.ops, 1)
@@ -142,7 +201,7 @@ func TestReadRepository(t *testing.T) {
 
 	testCommit("fix(semanticore)!: final fix")
 
-	repository, err = ReadRepository(mockRepo, true)
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
 	assert.NoError(t, err)
 	assert.Len(t, repository.tests, 1)
 	assert.Len(t, repository.ops, 1)
@@ -151,7 +210,7 @@ func TestReadRepository(t *testing.T) {
 	assert.Equal(t, 0, repository.Minor)
 	assert.Equal(t, 0, repository.Patch)
 
-	repository, err = ReadRepository(mockRepo, false)
+	repository, err = ReadRepository(mockRepo, Options{})
 	assert.NoError(t, err)
 	assert.Len(t, repository.tests, 1)
 	assert.Len(t, repository.ops, 1)
@@ -159,4 +218,363 @@ func TestReadRepository(t *testing.T) {
 	assert.Equal(t, 0, repository.Major)
 	assert.Equal(t, 1, repository.Minor)
 	assert.Equal(t, 0, repository.Patch)
+
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true, SemverZero: true})
+	assert.NoError(t, err)
+	assert.Equal(t, BumpMinor, repository.Bump)
+	assert.Equal(t, "v0.1.0", repository.Version())
+
+	repository, err = ReadRepository(mockRepo, Options{SemverZero: true, Stable: true})
+	assert.NoError(t, err)
+	assert.Equal(t, BumpMajor, repository.Bump)
+	assert.Equal(t, "v1.0.0", repository.Version())
+
+	_, err = ReadRepository(mockRepo, Options{Version: "0.0.2"})
+	assert.Error(t, err)
+
+	repository, err = ReadRepository(mockRepo, Options{Version: "2.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, BumpMajor, repository.Bump)
+	assert.Equal(t, "v2.0.0", repository.Version())
+	assert.Equal(t, "the version flag", repository.OverriddenBy)
+
+	vhash = testCommit("feat(semanticore): marketing release\n\nRelease-As: 0.3.0")
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
+	assert.NoError(t, err)
+	assert.Equal(t, BumpMinor, repository.Bump)
+	assert.Equal(t, "v0.3.0", repository.Version())
+	assert.Equal(t, "commit "+vhash.String()[:8], repository.OverriddenBy)
+
+	repository, err = ReadRepository(mockRepo, Options{Version: "v0.4.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.4.0", repository.Version())
+	assert.Equal(t, "the version flag", repository.OverriddenBy)
+	// skipped commits do not force the version
+	testCommit("feat(semanticore): skipped release\n\n[skip changelog]\n\nRelease-As: 9.0.0")
+	repository, err = ReadRepository(mockRepo, Options{CreateMajor: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.3.0", repository.Version())
+	assert.Equal(t, "commit "+vhash.String()[:8], repository.OverriddenBy)
+}
+
+func newTestRepository(t *testing.T) (*git.Repository, *git.Worktree, func(msg string) plumbing.Hash) {
+	mockRepo, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.NoError(t, err)
+
+	cfg, err := mockRepo.Config()
+	assert.NoError(t, err)
+	cfg.User.Email = "testing@example.com"
+	cfg.User.Name = "testing"
+	assert.NoError(t, mockRepo.SetConfig(cfg))
+
+	mockWt, err := mockRepo.Worktree()
+	assert.NoError(t, err)
+
+	file, err := mockWt.Filesystem.Create("test.file")
+	assert.NoError(t, err)
+
+	return mockRepo, mockWt, func(msg string) plumbing.Hash {
+		file.Write([]byte("msg"))
+		mockWt.Add("test.file")
+		hash, err := mockWt.Commit(msg, &git.CommitOptions{})
+		assert.NoError(t, err)
+		return hash
+	}
+}
+
+func TestReadRepositoryTags(t *testing.T) {
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+
+	hash := testCommit("feat: initial feature")
+	mockRepo.CreateTag("v1.2.0", hash, nil)
+	mockRepo.CreateTag("app-v2.0.0", hash, nil)
+	hash = testCommit("fix: first fix")
+	mockRepo.CreateTag("v1.1.1", hash, nil)
+	mockRepo.CreateTag("v1.0.0", hash, &git.CreateTagOptions{Message: "v1.0.0"})
+	mockRepo.CreateTag("docs-v9.9.9", hash, nil)
+	mockRepo.CreateTag("build-20240101.1.2.3", hash, nil)
+	mockRepo.CreateTag("v3.0.0-rc.1", hash, nil)
+	hash = testCommit("fix: second fix")
+
+	unreachable := testCommit("feat: abandoned feature")
+	mockRepo.CreateTag("v5.0.0", unreachable, nil)
+	assert.NoError(t, mockWt.Reset(&git.ResetOptions{Commit: hash, Mode: git.HardReset}))
+
+	repository, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.0", repository.Latest)
+	assert.Len(t, repository.fixes, 2)
+	assert.Equal(t, "v1.2.1", repository.Tag())
+
+	repository, err = ReadRepository(mockRepo, Options{TagPrefix: "app-"})
+	assert.NoError(t, err)
+	assert.Equal(t, "app-v2.0.0", repository.Latest)
+	assert.Len(t, repository.fixes, 2)
+	assert.Equal(t, "app-v2.0.1", repository.Tag())
+	assert.Equal(t, "v2.0.1", repository.Version())
+}
+
+func TestReadRepositoryIssueLinks(t *testing.T) {
+	mockRepo, _, testCommit := newTestRepository(t)
+
+	hash := testCommit("feat(export): csv export\n\nRefs: #12, PROJ-42")
+
+	repository, err := ReadRepository(mockRepo, Options{
+		Links: Links{Issue: "https://example.com/issues/{id}", Tracker: "https://jira.example.com/browse/{id}"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"**export:** csv export ([#12](https://example.com/issues/12), [PROJ-42](https://jira.example.com/browse/PROJ-42)) (" + hash.String()[:8] + ")"}, repository.Features)
+
+	repository, err = ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"**export:** csv export (#12, PROJ-42) (" + hash.String()[:8] + ")"}, repository.Features)
+}
+
+func TestReadRepositoryCommitLinks(t *testing.T) {
+	mockRepo, _, testCommit := newTestRepository(t)
+	links := Links{Commit: "https://example.com/commit/{hash}", Compare: "https://example.com/compare/{from}...{to}"}
+
+	hash := testCommit("feat: csv export")
+	repository, err := ReadRepository(mockRepo, Options{Links: links})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"csv export ([" + hash.String()[:8] + "](https://example.com/commit/" + hash.String() + "))"}, repository.Features)
+	assert.NotContains(t, repository.Changelog(), "Full diff")
+
+	mockRepo.CreateTag("v0.7.1", hash, nil)
+	testCommit("feat: json export")
+	repository, err = ReadRepository(mockRepo, Options{Links: links})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(repository.Changelog(), "\n\n[Full diff](https://example.com/compare/v0.7.1...v0.8.0)\n\n"))
+}
+
+func TestReadRepositoryBreakingChanges(t *testing.T) {
+	mockRepo, _, testCommit := newTestRepository(t)
+
+	feature := testCommit("feat(api)!: drop v1\n\nBREAKING CHANGE: the v1 endpoints were removed.\n\nUse v2 instead.")
+	fix := testCommit("fix!: stricter validation")
+	testCommit("fix: regular fix")
+
+	repository, err := ReadRepository(mockRepo, Options{CreateMajor: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", repository.Version())
+	assert.Contains(t, repository.Changelog(), "### ⚠️ Breaking Changes\n\n- stricter validation ("+fix.String()[:8]+")\n- **api:** drop v1 ("+feature.String()[:8]+")\n  the v1 endpoints were removed.\n\n  Use v2 instead.\n\n### Features\n\n")
+	assert.Equal(t, []string{"1 🆕 feature", "2 👾 fix"}, repository.Details)
+}
+
+func TestReadRepositorySplitSquash(t *testing.T) {
+	mockRepo, _, testCommit := newTestRepository(t)
+
+	hash := testCommit("feat: csv export (#12)\n\n* feat(export): add csv writer\n\n* fix: escape quotes\n\n* docs: document export")
+
+	repository, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"csv export (#12) (" + hash.String()[:8] + ")"}, repository.Features)
+	assert.Empty(t, repository.fixes)
+
+	repository, err = ReadRepository(mockRepo, Options{SplitSquash: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"**export:** add csv writer (" + hash.String()[:8] + ")"}, repository.Features)
+	assert.Equal(t, []string{"escape quotes (" + hash.String()[:8] + ")"}, repository.fixes)
+	assert.Equal(t, []string{"document export (" + hash.String()[:8] + ")"}, repository.docs)
+}
+
+func TestReadRepositoryCommitSource(t *testing.T) {
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+
+	base := testCommit("feat: base")
+	mockRepo.CreateTag("v1.0.0", base, nil)
+	merge := func(msg string, side string) (plumbing.Hash, plumbing.Hash) {
+		head, err := mockRepo.Head()
+		assert.NoError(t, err)
+		sideHash, err := mockWt.Commit(side, &git.CommitOptions{Parents: []plumbing.Hash{head.Hash()}, AllowEmptyCommits: true})
+		assert.NoError(t, err)
+		mergeHash, err := mockWt.Commit(msg, &git.CommitOptions{Parents: []plumbing.Hash{head.Hash(), sideHash}, AllowEmptyCommits: true})
+		assert.NoError(t, err)
+		return mergeHash, sideHash
+	}
+
+	github, _ := merge("Merge pull request #12 from x/y\n\nfeat: shiny feature", "wip github")
+	lookup, _ := merge("Merge pull request #13 from x/z", "wip lookup")
+	gitlab, _ := merge("Merge branch 'feature' into 'main'\n\nfix: gitlab fix\n\nSee merge request group/repo!7", "wip gitlab")
+	_, local := merge("Merge branch 'local'", "fix: local fix")
+	direct := testCommit("docs: direct commit")
+
+	repository, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Len(t, repository.other, 3)
+	assert.Equal(t, []string{"local fix (" + local.String()[:8] + ")"}, repository.fixes)
+	assert.Empty(t, repository.Features)
+
+	backend := &testBackend{mergeRequests: map[int]MergeRequestInfo{13: {Number: 13, Title: "fix: looked up fix"}}}
+	repository, err = ReadRepository(mockRepo, Options{CommitSource: CommitSourceFirstParent, Backend: backend})
+	assert.NoError(t, err)
+	assert.Empty(t, repository.other)
+	assert.Equal(t, []string{"shiny feature (#12) (" + github.String()[:8] + ")"}, repository.Features)
+	assert.Equal(t, []string{"gitlab fix (!7) (" + gitlab.String()[:8] + ")", "looked up fix (#13) (" + lookup.String()[:8] + ")"}, repository.fixes)
+	assert.Equal(t, []string{"direct commit (" + direct.String()[:8] + ")"}, repository.docs)
+
+	repository, err = ReadRepository(mockRepo, Options{CommitSource: CommitSourceFirstParent})
+	assert.NoError(t, err)
+	assert.Len(t, repository.fixes, 1)
+
+	repository, err = ReadRepository(mockRepo, Options{CommitSource: CommitSourceSquash})
+	assert.NoError(t, err)
+	assert.Empty(t, repository.other)
+	assert.Empty(t, repository.Features)
+	assert.Empty(t, repository.fixes)
+	assert.Equal(t, []string{"direct commit (" + direct.String()[:8] + ")"}, repository.docs)
+}
+
+func TestReadRepositoryFirstParentMergeBody(t *testing.T) {
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+
+	base := testCommit("feat: base")
+	mockRepo.CreateTag("v1.0.0", base, nil)
+	merge := func(msg string) plumbing.Hash {
+		head, err := mockRepo.Head()
+		assert.NoError(t, err)
+		side, err := mockWt.Commit("wip", &git.CommitOptions{Parents: []plumbing.Hash{head.Hash()}, AllowEmptyCommits: true})
+		assert.NoError(t, err)
+		hash, err := mockWt.Commit(msg, &git.CommitOptions{Parents: []plumbing.Hash{head.Hash(), side}, AllowEmptyCommits: true})
+		assert.NoError(t, err)
+		return hash
+	}
+
+	merge("Merge pull request #12 from x/y\n\nfeat: new api\n\nBREAKING CHANGE: v1 is gone")
+	merge("Merge pull request #13 from x/z")
+	merge("Merge branch 'feature' into 'main'\n\nfix: gitlab fix\n\nRelease-As: 3.1.0\n\nSee merge request group/repo!7")
+
+	backend := &testBackend{mergeRequests: map[int]MergeRequestInfo{13: {Number: 13, Title: "fix: looked up fix"}}}
+	repository, err := ReadRepository(mockRepo, Options{CommitSource: CommitSourceFirstParent, Backend: backend})
+	assert.NoError(t, err)
+	assert.True(t, repository.Breaking)
+	assert.Len(t, repository.breaking, 1)
+	assert.Contains(t, repository.breaking[0], "v1 is gone")
+	assert.Len(t, repository.fixes, 2)
+	assert.Equal(t, "v3.1.0", repository.Tag())
+}
+
+func TestReadRepositoryMergeRequestLinks(t *testing.T) {
+	mockRepo, _, testCommit := newTestRepository(t)
+
+	squashed := testCommit("feat: csv export (#12)\n\n* feat: csv writer\n\n* fix: escape quotes")
+	direct := testCommit("fix: direct fix")
+
+	backend := &testBackend{commitMergeRequests: map[string]MergeRequestInfo{
+		squashed.String(): {Number: 12, Ref: "#12", URL: "https://example.com/pull/12", Author: "octocat"},
+	}}
+	repository, err := ReadRepository(mockRepo, Options{Backend: backend, MergeRequestLinks: true, SplitSquash: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"csv writer ([#12](https://example.com/pull/12) by @octocat) (" + squashed.String()[:8] + ")"}, repository.Features)
+	assert.Equal(t, []string{"direct fix (" + direct.String()[:8] + ")", "escape quotes ([#12](https://example.com/pull/12) by @octocat) (" + squashed.String()[:8] + ")"}, repository.fixes)
+	assert.Equal(t, 2, backend.commitLookups)
+
+	repository, err = ReadRepository(mockRepo, Options{Backend: backend, MergeRequestLinks: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"csv export ([#12](https://example.com/pull/12) by @octocat) (" + squashed.String()[:8] + ")"}, repository.Features)
+
+	referenced := testCommit("feat: pdf export (#13)\n\nRefs: #7")
+	backend.commitMergeRequests[referenced.String()] = MergeRequestInfo{Number: 13, Ref: "#13", URL: "https://example.com/pull/13"}
+	repository, err = ReadRepository(mockRepo, Options{Backend: backend, MergeRequestLinks: true})
+	assert.NoError(t, err)
+	assert.Contains(t, repository.Features, "pdf export (#7) ([#13](https://example.com/pull/13)) ("+referenced.String()[:8]+")")
+}
+
+func TestReadRepositoryMergedBranchRange(t *testing.T) {
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+
+	base := testCommit("feat: base")
+	long, err := mockWt.Commit("fix: long running fix", &git.CommitOptions{Parents: []plumbing.Hash{base}, AllowEmptyCommits: true})
+	assert.NoError(t, err)
+	longer, err := mockWt.Commit("fix: longer running fix", &git.CommitOptions{Parents: []plumbing.Hash{long}, AllowEmptyCommits: true})
+	assert.NoError(t, err)
+	released, err := mockWt.Commit("feat: released feature", &git.CommitOptions{Parents: []plumbing.Hash{base}, AllowEmptyCommits: true})
+	assert.NoError(t, err)
+	mockRepo.CreateTag("v1.0.0", released, nil)
+	_, err = mockWt.Commit("Merge branch 'long'", &git.CommitOptions{Parents: []plumbing.Hash{released, longer}, AllowEmptyCommits: true})
+	assert.NoError(t, err)
+
+	repository, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Empty(t, repository.Features)
+	assert.Equal(t, []string{"longer running fix (" + longer.String()[:8] + ")", "long running fix (" + long.String()[:8] + ")"}, repository.fixes)
+	assert.Equal(t, "v1.0.1", repository.Tag())
+}
+
+// generateHistory stores commits directly without worktree, every tenth commit merges a side branch
+// and the commit unreleased commits before head is tagged as v1.0.0
+func generateHistory(tb testing.TB, commits, unreleased int) *git.Repository {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	assert.NoError(tb, err)
+
+	store := func(encoder interface {
+		Encode(plumbing.EncodedObject) error
+	}) plumbing.Hash {
+		obj := repo.Storer.NewEncodedObject()
+		assert.NoError(tb, encoder.Encode(obj))
+		hash, err := repo.Storer.SetEncodedObject(obj)
+		assert.NoError(tb, err)
+		return hash
+	}
+	tree := store(&object.Tree{})
+	when := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	commit := func(msg string, parents ...plumbing.Hash) plumbing.Hash {
+		when = when.Add(time.Minute)
+		signature := object.Signature{Name: "testing", Email: "testing@example.com", When: when}
+		return store(&object.Commit{Author: signature, Committer: signature, Message: msg, TreeHash: tree, ParentHashes: parents})
+	}
+
+	var history []plumbing.Hash
+	head := commit("feat: initial commit")
+	history = append(history, head)
+	for i := 1; i < commits; i++ {
+		if i%10 == 0 && len(history) > 5 {
+			side := commit(fmt.Sprintf("fix: side fix %d", i), history[len(history)-5])
+			head = commit(fmt.Sprintf("Merge branch 'side-%d'", i), head, side)
+		} else {
+			head = commit(fmt.Sprintf("feat: feature %d", i), head)
+		}
+		history = append(history, head)
+		if i == commits-unreleased {
+			assert.NoError(tb, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.0.0"), head)))
+		}
+	}
+	assert.NoError(tb, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.Master, head)))
+	return repo
+}
+
+func BenchmarkReadRepository(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, commits := range []int{1000, 10000, 50000} {
+		repo := generateHistory(b, commits, 100)
+		b.Run(fmt.Sprintf("%d commits", commits), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				repository, err := ReadRepository(repo, Options{})
+				if err != nil || repository.Latest != "v1.0.0" {
+					b.Fatalf("unexpected release %v: %s", repository, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadRepositoryReleaseCommitTemplate(t *testing.T) {
+	mockRepo, _, testCommit := newTestRepository(t)
+
+	testCommit("feat: initial feature")
+	release := testCommit("chore(release): v0.1.0")
+	testCommit("fix: first fix")
+
+	repository, err := ReadRepository(mockRepo, Options{ReleaseCommitTemplate: "chore(release): {version}"})
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.1.0", repository.Latest)
+	assert.Equal(t, release.String(), repository.unreleased)
+	assert.Equal(t, "v0.1.1", repository.Tag())
+
+	repository, err = ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Empty(t, repository.unreleased)
+	assert.Equal(t, "v0.1.0", repository.Tag())
 }
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var revertHashRegexp = regexp.MustCompile(`This reverts commit ([0-9a-fA-F]{7,40})`)
+var revertSubjectRegexp = regexp.MustCompile(`^Revert "(.*)"(\s+\(#\d+\))?$`)
+
+// pairReverts returns the commits which cancel each other out: a revert together with the reverted commit.
+// Reverts are matched by the (abbreviated) hash of `This reverts commit <hash>` or by the subject of `Revert "<subject>"`,
+// the reverted commit has to be part of the logs, which are ordered from newest to oldest.
+// A revert which is reverted itself re-applies the original commit, so only the two newest commits of a chain are paired.
+func pairReverts(logs []*object.Commit) map[plumbing.Hash]bool {
+	dropped := make(map[plumbing.Hash]bool)
+	for i, commit := range logs {
+		if dropped[commit.Hash] {
+			continue
+		}
+		if target := revertTarget(commit, logs[i+1:], dropped); target != nil {
+			dropped[commit.Hash] = true
+			dropped[target.Hash] = true
+		}
+	}
+	return dropped
+}
+
+// revertTarget finds the commit reverted by commit within the older commits
+func revertTarget(commit *object.Commit, older []*object.Commit, dropped map[plumbing.Hash]bool) *object.Commit {
+	msg := strings.TrimSpace(commit.Message)
+	if match := revertHashRegexp.FindStringSubmatch(msg); match != nil {
+		prefix := strings.ToLower(match[1])
+		for _, c := range older {
+			if !dropped[c.Hash] && strings.HasPrefix(c.Hash.String(), prefix) {
+				return c
+			}
+		}
+		return nil
+	}
+
+	match := revertSubjectRegexp.FindStringSubmatch(subject(msg))
+	if match == nil {
+		return nil
+	}
+	for _, c := range older {
+		if !dropped[c.Hash] && subject(c.Message) == match[1] {
+			return c
+		}
+	}
+	return nil
+}
+
+func subject(msg string) string {
+	return strings.TrimSpace(strings.SplitN(strings.TrimSpace(msg), "\n", 2)[0])
+}
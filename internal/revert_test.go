@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRepositoryReverts(t *testing.T) {
+	mockRepo, _, testCommit := newTestRepository(t)
+
+	released := testCommit("feat: released feature")
+	mockRepo.CreateTag("v1.0.0", released, nil)
+
+	full := testCommit("feat: full hash")
+	testCommit(fmt.Sprintf("Revert \"feat: full hash\"\n\nThis reverts commit %s.", full))
+
+	short := testCommit("feat: short hash")
+	testCommit(fmt.Sprintf("Revert \"feat: something else\"\n\nThis reverts commit %s.", short.String()[:7]))
+
+	testCommit("feat: by subject (#12)")
+	testCommit(`Revert "feat: by subject (#12)" (#13)`)
+
+	reapplied := testCommit("feat: re-applied")
+	revert := testCommit(fmt.Sprintf("Revert \"feat: re-applied\"\n\nThis reverts commit %s.", reapplied))
+	testCommit(fmt.Sprintf("Revert \"Revert \"feat: re-applied\"\"\n\nThis reverts commit %s.", revert))
+
+	twice := testCommit("feat: reverted twice")
+	revert = testCommit(fmt.Sprintf("Revert \"feat: reverted twice\"\n\nThis reverts commit %s.", twice))
+	revert = testCommit(fmt.Sprintf("Revert \"Revert \"feat: reverted twice\"\"\n\nThis reverts commit %s.", revert))
+	testCommit(fmt.Sprintf("Revert \"Revert \"Revert \"feat: reverted twice\"\"\"\n\nThis reverts commit %s.", revert))
+
+	releasedRevert := testCommit(fmt.Sprintf("Revert \"feat: released feature\"\n\nThis reverts commit %s.", released))
+
+	repository, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"re-applied (" + reapplied.String()[:8] + ")"}, repository.Features)
+	assert.Equal(t, []string{`Revert "feat: released feature" (` + releasedRevert.String()[:8] + ")"}, repository.other)
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// normalizeScope replaces a scope by its alias target
+func normalizeScope(scope string, aliases map[string]string) string {
+	if alias, ok := aliases[scope]; ok {
+		return alias
+	}
+	return scope
+}
+
+// checkScopes handles scopes which are not part of the known scopes according to the UnknownScopes option
+func checkScopes(entries []changelogEntry, options Options) error {
+	if len(options.Scopes) == 0 || options.UnknownScopes == "" {
+		return nil
+	}
+
+	var unknown []string
+	for _, entry := range entries {
+		if entry.Scope == "" || slices.Contains(options.Scopes, entry.Scope) {
+			continue
+		}
+		unknown = append(unknown, fmt.Sprintf("%s (%s)", entry.Scope, entry.commit.Hash.String()[:8]))
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	if options.UnknownScopes == "fail" {
+		return fmt.Errorf("unknown scopes: %s", strings.Join(unknown, ", "))
+	}
+	log.Printf("[semanticore] unknown scopes: %s", strings.Join(unknown, ", "))
+	return nil
+}
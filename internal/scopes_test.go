@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRepositoryScopes(t *testing.T) {
+	mockRepo, _, testCommit := newTestRepository(t)
+
+	api := testCommit("feat(api): export endpoint")
+	ui := testCommit("feat(frontend): export button")
+	unscoped := testCommit("feat: export")
+	deps := testCommit("fix(dependencies): update go-git")
+	cli := testCommit("fix(cli): flag parsing")
+
+	options := Options{
+		Scopes:       []string{"api", "ui", "deps"},
+		ScopeAliases: map[string]string{"frontend": "ui", "dependencies": "deps"},
+	}
+	repository, err := ReadRepository(mockRepo, options)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"export (" + unscoped.String()[:8] + ")",
+		"**ui:** export button (" + ui.String()[:8] + ")",
+		"**api:** export endpoint (" + api.String()[:8] + ")",
+	}, repository.Features)
+
+	options.UnknownScopes = "warn"
+	_, err = ReadRepository(mockRepo, options)
+	assert.NoError(t, err)
+
+	options.UnknownScopes = "fail"
+	_, err = ReadRepository(mockRepo, options)
+	assert.ErrorContains(t, err, "unknown scopes: cli ("+cli.String()[:8]+")")
+
+	options.UnknownScopes = ""
+	options.GroupByScope = true
+	repository, err = ReadRepository(mockRepo, options)
+	assert.NoError(t, err)
+	assert.Contains(t, repository.Changelog(), "### Features\n\n"+
+		"- export ("+unscoped.String()[:8]+")\n\n"+
+		"#### api\n\n"+
+		"- export endpoint ("+api.String()[:8]+")\n\n"+
+		"#### ui\n\n"+
+		"- export button ("+ui.String()[:8]+")\n\n"+
+		"### Fixes\n\n"+
+		"#### cli\n\n"+
+		"- flag parsing ("+cli.String()[:8]+")\n\n"+
+		"#### deps\n\n"+
+		"- update go-git ("+deps.String()[:8]+")\n\n")
+}
+
+func TestLintMessageScopeAliases(t *testing.T) {
+	rules := LintRules{Scopes: []string{"ui"}, ScopeAliases: map[string]string{"frontend": "ui"}}
+	assert.Empty(t, LintMessage("feat(frontend): export button", rules))
+	assert.Len(t, LintMessage("feat(backend): export", rules), 1)
+}
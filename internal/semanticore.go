@@ -1,13 +1,43 @@
 package internal
 
 import (
+	"errors"
+
 	"github.com/go-git/go-git/v5/plumbing/transport"
 )
 
+// commentsPerPage is the page size when listing merge request comments, pages are requested until one is not full
+const commentsPerPage = 100
+
+// errNotFound is wrapped by backend requests which fail because the resource does not exist
+var errNotFound = errors.New("not found")
+
 type Backend interface {
 	transport.AuthMethod
+	// Release creates the release of a tag, the tag is created at ref unless ref is empty and the tag already exists
 	Release(tag, ref, changelog string) error
+	// HasRelease reports whether the release of a tag exists
+	HasRelease(tag string) (bool, error)
 	MergeRequest(target, title, description, labels string) error
 	CloseMergeRequest() error
+	// ReleaseMergeRequest returns the number of the open merge request of the release branch, it is 0 if there is none
+	ReleaseMergeRequest() (int, error)
 	MainBranch() (string, error)
+	Links() Links
+	FindMergeRequest(number int) (MergeRequestInfo, error)
+	FindCommitMergeRequest(sha string) (MergeRequestInfo, error)
+	CommentMergeRequest(number int, body string) error
+	// MergeRequestComments returns the bodies of all comments on a merge request
+	MergeRequestComments(number int) ([]string, error)
+	FindUser(email string) (string, error)
+}
+
+// MergeRequestInfo describes a pull or merge request on the backend
+type MergeRequestInfo struct {
+	Number int
+	// Ref is the reference of the merge request in markdown, e.g. `#12` on Github or `!12` on Gitlab
+	Ref    string
+	Title  string
+	URL    string
+	Author string
 }
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// ErrShallowHistory is returned by ReadRepository if the history of a shallow clone ends before the latest version
+var ErrShallowHistory = errors.New("the history of the shallow clone does not reach the latest version")
+
+const (
+	// deepenStep is the depth of the first fetch, it is doubled every round
+	deepenStep = 50
+	// deepenMaxDepth fetches the complete history, like `git fetch --unshallow`
+	deepenMaxDepth = 0x7fffffff
+)
+
+// shallowCommits returns the boundary commits of a shallow clone, whose parents are missing
+func shallowCommits(repo *git.Repository) (map[plumbing.Hash]bool, error) {
+	shallowStorer, ok := repo.Storer.(storer.ShallowStorer)
+	if !ok {
+		return nil, nil
+	}
+	hashes, err := shallowStorer.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read shallow commits: %w", err)
+	}
+	shallow := make(map[plumbing.Hash]bool, len(hashes))
+	for _, hash := range hashes {
+		shallow[hash] = true
+	}
+	return shallow, nil
+}
+
+// missingParents returns the parents of the shallow commits, they are skipped when walking the history
+func missingParents(repo *git.Repository, shallow map[plumbing.Hash]bool) []plumbing.Hash {
+	var missing []plumbing.Hash
+	for hash := range shallow {
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			continue
+		}
+		for _, parent := range commit.ParentHashes {
+			if _, err := repo.Storer.EncodedObject(plumbing.CommitObject, parent); err != nil {
+				missing = append(missing, parent)
+			}
+		}
+	}
+	return missing
+}
+
+// checkShallowHistory fails if the walked commits reach the boundary of a shallow clone
+func checkShallowHistory(commits []*object.Commit, shallow map[plumbing.Hash]bool) error {
+	if len(shallow) == 0 {
+		return nil
+	}
+	for _, commit := range commits {
+		if shallow[commit.Hash] {
+			return fmt.Errorf("%w: commit %s has no parents in the clone, fetch more history or use -deepen", ErrShallowHistory, commit.Hash)
+		}
+	}
+	return nil
+}
+
+// pruneShallowCommits removes commits from the shallow file whose parents were fetched,
+// go-git only adds new boundaries when deepening
+func pruneShallowCommits(repo *git.Repository) (int, error) {
+	shallowStorer, ok := repo.Storer.(storer.ShallowStorer)
+	if !ok {
+		return 0, nil
+	}
+	shallow, err := shallowCommits(repo)
+	if err != nil {
+		return 0, err
+	}
+	missing := make(map[plumbing.Hash]bool)
+	for _, hash := range missingParents(repo, shallow) {
+		missing[hash] = true
+	}
+
+	var remaining []plumbing.Hash
+	for hash := range shallow {
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			continue
+		}
+		for _, parent := range commit.ParentHashes {
+			if missing[parent] {
+				remaining = append(remaining, hash)
+				break
+			}
+		}
+	}
+	if len(remaining) == len(shallow) {
+		return len(remaining), nil
+	}
+	return len(remaining), shallowStorer.SetShallow(remaining)
+}
+
+// DeepenHistory fetches the history of a shallow clone from origin until ReadRepository reaches the latest version.
+// The depth is doubled every round until the complete history is fetched. Complete clones return early without reading the history.
+func DeepenHistory(repo *git.Repository, options Options, auth transport.AuthMethod) error {
+	shallow, err := shallowCommits(repo)
+	if err != nil {
+		return err
+	}
+	if len(shallow) == 0 {
+		return nil
+	}
+
+	// only the history is read, no backend is queried
+	probe := Options{
+		TagPrefix:    options.TagPrefix,
+		CommitSource: options.CommitSource,
+		Version:      options.Version,
+
+		ReleaseCommitTemplate: options.ReleaseCommitTemplate,
+	}
+
+	for depth := deepenStep; ; depth *= 2 {
+		_, err := ReadRepository(repo, probe)
+		if !errors.Is(err, ErrShallowHistory) {
+			return nil
+		}
+		if depth > deepenMaxDepth/2 {
+			depth = deepenMaxDepth
+		}
+
+		log.Printf("[semanticore] shallow clone does not reach the latest version, fetching %d commits of history", depth)
+		err = repo.Fetch(&git.FetchOptions{
+			RemoteName: "origin",
+			Depth:      depth,
+			Tags:       git.AllTags,
+			Auth:       auth,
+		})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return fmt.Errorf("unable to deepen shallow clone: %w", err)
+		}
+		remaining, err := pruneShallowCommits(repo)
+		if err != nil {
+			return fmt.Errorf("unable to update shallow commits: %w", err)
+		}
+		if remaining == 0 {
+			log.Printf("[semanticore] fetched the complete history")
+			return nil
+		}
+
+		if depth == deepenMaxDepth {
+			_, err := ReadRepository(repo, probe)
+			if errors.Is(err, ErrShallowHistory) {
+				return fmt.Errorf("%w after fetching the complete history of origin", err)
+			}
+			return nil
+		}
+	}
+}
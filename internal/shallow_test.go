@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShallowClone(t *testing.T) {
+	remoteDir := t.TempDir()
+	_, err := git.PlainInit(remoteDir, true)
+	assert.NoError(t, err)
+
+	mockRepo, _, testCommit := newTestRepository(t)
+	testCommit("feat: initial feature")
+	hash := testCommit("feat: csv export")
+	mockRepo.CreateTag("v1.0.0", hash, nil)
+	for _, msg := range []string{"fix: first fix", "fix: second fix", "feat: pdf export", "docs: readme"} {
+		testCommit(msg)
+	}
+	_, err = mockRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}})
+	assert.NoError(t, err)
+	assert.NoError(t, mockRepo.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"}}))
+
+	complete, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.1.0", complete.Tag())
+
+	t.Run("deep enough", func(t *testing.T) {
+		clone, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{URL: remoteDir, Depth: 5, Tags: git.AllTags})
+		assert.NoError(t, err)
+
+		repository, err := ReadRepository(clone, Options{})
+		assert.NoError(t, err)
+		assert.Equal(t, "v1.1.0", repository.Tag())
+		assert.Len(t, repository.fixes, 2)
+	})
+
+	t.Run("too shallow", func(t *testing.T) {
+		clone, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{URL: remoteDir, Depth: 2, Tags: git.AllTags})
+		assert.NoError(t, err)
+
+		_, err = ReadRepository(clone, Options{})
+		assert.ErrorIs(t, err, ErrShallowHistory)
+
+		assert.NoError(t, DeepenHistory(clone, Options{}, nil))
+		shallow, err := shallowCommits(clone)
+		assert.NoError(t, err)
+		assert.Empty(t, shallow)
+
+		repository, err := ReadRepository(clone, Options{})
+		assert.NoError(t, err)
+		assert.Equal(t, "v1.1.0", repository.Tag())
+		assert.Equal(t, complete.fixes, repository.fixes)
+	})
+
+	t.Run("complete clone", func(t *testing.T) {
+		assert.NoError(t, DeepenHistory(mockRepo, Options{}, nil))
+	})
+}
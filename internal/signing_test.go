@@ -1,9 +1,18 @@
 package internal
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/go-git/go-git/v5"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,3 +64,106 @@ func TestTryCreateSignKey(t *testing.T) {
 		assert.Nil(t, entity, "Expected nil entity when no key is provided")
 	})
 }
+
+func newTestGPGKey(t *testing.T, name string, config *packet.Config) *openpgp.Entity {
+	if config == nil {
+		config = &packet.Config{}
+	}
+	config.Algorithm = packet.PubKeyAlgoEdDSA
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", config)
+	assert.NoError(t, err)
+	return entity
+}
+
+func armorGPGKeys(t *testing.T, passphrase string, entities ...*openpgp.Entity) string {
+	var b bytes.Buffer
+	w, err := armor.Encode(&b, openpgp.PrivateKeyType, nil)
+	assert.NoError(t, err)
+	for _, entity := range entities {
+		if passphrase != "" {
+			assert.NoError(t, entity.EncryptPrivateKeys([]byte(passphrase), nil))
+		}
+		assert.NoError(t, entity.SerializePrivateWithoutSigning(w, nil))
+	}
+	assert.NoError(t, w.Close())
+	return b.String()
+}
+
+func verifyGPGSignature(t *testing.T, signer git.Signer, entity *openpgp.Entity) *openpgp.Entity {
+	signature, err := signer.Sign(strings.NewReader("message"))
+	assert.NoError(t, err)
+	signedBy, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity}, strings.NewReader("message"), bytes.NewReader(signature), nil)
+	assert.NoError(t, err)
+	return signedBy
+}
+
+func TestTryCreateSignerPassphrase(t *testing.T) {
+	entity := newTestGPGKey(t, "encrypted", nil)
+	t.Setenv("SEMANTICORE_SIGN_KEY", armorGPGKeys(t, "secret", entity))
+
+	_, err := TryCreateSigner(SignKeyOptions{})
+	assert.ErrorContains(t, err, "is encrypted, provide its passphrase")
+
+	t.Setenv("SEMANTICORE_SIGN_KEY_PASSPHRASE", "wrong")
+	_, err = TryCreateSigner(SignKeyOptions{})
+	assert.ErrorContains(t, err, "unable to decrypt GPG key")
+
+	t.Setenv("SEMANTICORE_SIGN_KEY_PASSPHRASE", "secret")
+	signer, err := TryCreateSigner(SignKeyOptions{})
+	assert.NoError(t, err)
+	verifyGPGSignature(t, signer, entity)
+
+	passphraseFile := filepath.Join(t.TempDir(), "passphrase")
+	assert.NoError(t, os.WriteFile(passphraseFile, []byte("secret\n"), 0600))
+	_, err = TryCreateSigner(SignKeyOptions{PassphraseFile: passphraseFile})
+	assert.ErrorContains(t, err, "both a passphrase file and SEMANTICORE_SIGN_KEY_PASSPHRASE")
+
+	t.Setenv("SEMANTICORE_SIGN_KEY_PASSPHRASE", "")
+	signer, err = TryCreateSigner(SignKeyOptions{PassphraseFile: passphraseFile})
+	assert.NoError(t, err)
+	verifyGPGSignature(t, signer, entity)
+}
+
+func TestTryCreateSignerKeySelection(t *testing.T) {
+	first := newTestGPGKey(t, "first", nil)
+	second := newTestGPGKey(t, "second", nil)
+	assert.NoError(t, second.AddSigningSubkey(&packet.Config{Algorithm: packet.PubKeyAlgoEdDSA}))
+	signingSubkey := second.Subkeys[len(second.Subkeys)-1].PublicKey
+	encryptionSubkey := second.Subkeys[0].PublicKey
+	t.Setenv("SEMANTICORE_SIGN_KEY", armorGPGKeys(t, "", first, second))
+
+	_, err := TryCreateSigner(SignKeyOptions{})
+	assert.ErrorContains(t, err, "the keyring contains 2 GPG private keys, select one by its key ID: "+fingerprint(first.PrimaryKey)+", "+fingerprint(second.PrimaryKey))
+
+	signer, err := TryCreateSigner(SignKeyOptions{KeyID: fingerprint(first.PrimaryKey)})
+	assert.NoError(t, err)
+	assert.Equal(t, first.PrimaryKey.KeyId, signer.(openpgpSigner).keyID)
+	verifyGPGSignature(t, signer, first)
+
+	signer, err = TryCreateSigner(SignKeyOptions{KeyID: fmt.Sprintf("0x%x", second.PrimaryKey.KeyId)})
+	assert.NoError(t, err)
+	assert.Equal(t, signingSubkey.KeyId, signer.(openpgpSigner).keyID, "the signing subkey is preferred")
+
+	signer, err = TryCreateSigner(SignKeyOptions{KeyID: fmt.Sprintf("%016X", signingSubkey.KeyId)})
+	assert.NoError(t, err)
+	assert.Equal(t, signingSubkey.KeyId, signer.(openpgpSigner).keyID)
+	assert.Equal(t, second.PrimaryKey.KeyId, verifyGPGSignature(t, signer, second).PrimaryKey.KeyId)
+
+	_, err = TryCreateSigner(SignKeyOptions{KeyID: fmt.Sprintf("%016X", encryptionSubkey.KeyId)})
+	assert.ErrorContains(t, err, "is not a signing key")
+
+	_, err = TryCreateSigner(SignKeyOptions{KeyID: "DEADBEEFDEADBEEF"})
+	assert.ErrorContains(t, err, "no GPG private key matches the key ID DEADBEEFDEADBEEF")
+}
+
+func TestTryCreateSignerExpiredKey(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour)
+	entity := newTestGPGKey(t, "expired", &packet.Config{
+		Time:            func() time.Time { return created },
+		KeyLifetimeSecs: 3600,
+	})
+	t.Setenv("SEMANTICORE_SIGN_KEY", armorGPGKeys(t, "", entity))
+
+	_, err := TryCreateSigner(SignKeyOptions{})
+	assert.ErrorContains(t, err, "GPG key "+fingerprint(entity.PrimaryKey)+" expired on "+created.Add(time.Hour).Format("2006-01-02"))
+}
@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSH signatures follow the SSHSIG format of OpenSSH as used by git with `gpg.format=ssh`,
+// see https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+const (
+	sshSigMagic     = "SSHSIG"
+	sshSigVersion   = 1
+	sshSigNamespace = "git"
+	sshSigPEMType   = "SSH SIGNATURE"
+)
+
+// sshSignedData is the blob which is actually signed
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// sshSignature is the blob of an armored SSH signature, without the magic preamble
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSigner signs git objects with an SSH key
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+func loadSSHSigner(content string, passphrase []byte) (*sshSigner, error) {
+	var signer ssh.Signer
+	var err error
+	if len(passphrase) > 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(content), passphrase)
+	} else {
+		signer, err = ssh.ParsePrivateKey([]byte(content))
+	}
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return nil, fmt.Errorf("SSH key is encrypted, provide its passphrase with SEMANTICORE_SIGN_KEY_PASSPHRASE or a passphrase file: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key: %w", err)
+	}
+	return &sshSigner{signer: signer}, nil
+}
+
+func (s *sshSigner) Sign(message io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, fmt.Errorf("unable to read message: %w", err)
+	}
+	signedData := sshSignedData{
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          h.Sum(nil),
+	}
+
+	var signature *ssh.Signature
+	var err error
+	data := append([]byte(sshSigMagic), ssh.Marshal(signedData)...)
+	if algorithmSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// SHA-1 RSA signatures are rejected by OpenSSH
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = s.signer.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign message: %w", err)
+	}
+
+	blob := append([]byte(sshSigMagic), ssh.Marshal(sshSignature{
+		Version:       sshSigVersion,
+		PublicKey:     s.signer.PublicKey().Marshal(),
+		Namespace:     signedData.Namespace,
+		HashAlgorithm: signedData.HashAlgorithm,
+		Signature:     ssh.Marshal(signature),
+	})...)
+	return armorSSHSignature(blob), nil
+}
+
+// armorSSHSignature encodes the signature like ssh-keygen with lines of 70 characters
+func armorSSHSignature(blob []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var b bytes.Buffer
+	b.WriteString("-----BEGIN " + sshSigPEMType + "-----\n")
+	for len(encoded) > 70 {
+		b.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString("-----END " + sshSigPEMType + "-----\n")
+	return b.Bytes()
+}
+
+// verifySSHSignature verifies an armored SSH signature of the message and returns the public key which signed it
+func verifySSHSignature(message []byte, armored string) (ssh.PublicKey, error) {
+	block, _ := pem.Decode([]byte(armored))
+	if block == nil || block.Type != sshSigPEMType {
+		return nil, errors.New("no SSH signature found")
+	}
+	if !bytes.HasPrefix(block.Bytes, []byte(sshSigMagic)) {
+		return nil, errors.New("invalid SSH signature preamble")
+	}
+	var sig sshSignature
+	if err := ssh.Unmarshal(block.Bytes[len(sshSigMagic):], &sig); err != nil {
+		return nil, fmt.Errorf("unable to decode SSH signature: %w", err)
+	}
+	if sig.Version != sshSigVersion {
+		return nil, fmt.Errorf("unsupported SSH signature version %d", sig.Version)
+	}
+	if sig.Namespace != sshSigNamespace {
+		return nil, fmt.Errorf("unexpected SSH signature namespace %q", sig.Namespace)
+	}
+
+	publicKey, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse public key of SSH signature: %w", err)
+	}
+	signature := new(ssh.Signature)
+	if err := ssh.Unmarshal(sig.Signature, signature); err != nil {
+		return nil, fmt.Errorf("unable to decode SSH signature: %w", err)
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha512":
+		h = sha512.New()
+	case "sha256":
+		h = sha256.New()
+	default:
+		return nil, fmt.Errorf("unsupported SSH signature hash algorithm %q", sig.HashAlgorithm)
+	}
+	h.Write(message)
+	data := append([]byte(sshSigMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+	if err := publicKey.Verify(data, signature); err != nil {
+		return nil, fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	return publicKey, nil
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func testSSHKey(t *testing.T, key crypto.PrivateKey) (string, ssh.PublicKey) {
+	block, err := ssh.MarshalPrivateKey(key, "semanticore@example.com")
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(block)), signer.PublicKey()
+}
+
+// sshKeygenVerify verifies the signature with OpenSSH if it is installed
+func sshKeygenVerify(t *testing.T, publicKey ssh.PublicKey, message, signature []byte) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Log("ssh-keygen not found, skipping verification with OpenSSH")
+		return
+	}
+	dir := t.TempDir()
+	allowedSigners := filepath.Join(dir, "allowed_signers")
+	signatureFile := filepath.Join(dir, "message.sig")
+	assert.NoError(t, os.WriteFile(allowedSigners, []byte("semanticore@example.com "+string(ssh.MarshalAuthorizedKey(publicKey))), 0600))
+	assert.NoError(t, os.WriteFile(signatureFile, signature, 0600))
+
+	cmd := exec.Command("ssh-keygen", "-Y", "verify", "-f", allowedSigners, "-I", "semanticore@example.com", "-n", "git", "-s", signatureFile)
+	cmd.Stdin = strings.NewReader(string(message))
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+}
+
+func unsignedObject(t *testing.T, obj interface {
+	EncodeWithoutSignature(plumbing.EncodedObject) error
+}) []byte {
+	encoded := &plumbing.MemoryObject{}
+	assert.NoError(t, obj.EncodeWithoutSignature(encoded))
+	reader, err := encoded.Reader()
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return content
+}
+
+func TestSSHSigner(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	for name, key := range map[string]crypto.PrivateKey{"ed25519": ed25519Key, "rsa": rsaKey} {
+		t.Run(name, func(t *testing.T) {
+			content, publicKey := testSSHKey(t, key)
+			t.Setenv("SEMANTICORE_SIGN_KEY", content)
+			signer, err := TryCreateSigner(SignKeyOptions{})
+			assert.NoError(t, err)
+			assert.IsType(t, &sshSigner{}, signer)
+
+			message := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nRelease v1.0.0\n")
+			signature, err := signer.Sign(strings.NewReader(string(message)))
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(string(signature), "-----BEGIN SSH SIGNATURE-----\n"))
+
+			signedBy, err := verifySSHSignature(message, string(signature))
+			assert.NoError(t, err)
+			assert.Equal(t, publicKey.Marshal(), signedBy.Marshal())
+
+			_, err = verifySSHSignature([]byte("tampered"), string(signature))
+			assert.Error(t, err)
+
+			sshKeygenVerify(t, publicKey, message, signature)
+		})
+	}
+}
+
+func TestSSHSignedCommitAndTag(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	content, publicKey := testSSHKey(t, key)
+	t.Setenv("SEMANTICORE_SIGN_KEY", content)
+	signer, err := TryCreateSigner(SignKeyOptions{})
+	assert.NoError(t, err)
+
+	mockRepo, mockWt, _ := newTestRepository(t)
+	signature := &object.Signature{Name: "Semanticore Bot", Email: "semanticore@aoe.com", When: time.Now()}
+	hash, err := mockWt.Commit("Release v1.0.0", &git.CommitOptions{Author: signature, AllowEmptyCommits: true, Signer: signer})
+	assert.NoError(t, err)
+
+	commit, err := mockRepo.CommitObject(hash)
+	assert.NoError(t, err)
+	encoded := unsignedObject(t, commit)
+	signedBy, err := verifySSHSignature(encoded, commit.PGPSignature)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey.Marshal(), signedBy.Marshal())
+	sshKeygenVerify(t, publicKey, encoded, []byte(commit.PGPSignature))
+
+	ref, err := createTag(mockRepo, "v1.0.0", hash, signature, "Release v1.0.0", signer)
+	assert.NoError(t, err)
+	tag, err := mockRepo.TagObject(ref.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, "Release v1.0.0\n", tag.Message)
+	encoded = unsignedObject(t, tag)
+	_, err = verifySSHSignature(encoded, tag.PGPSignature)
+	assert.NoError(t, err)
+	sshKeygenVerify(t, publicKey, encoded, []byte(tag.PGPSignature))
+}
+
+func TestSSHSignerPassphrase(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("secret"))
+	assert.NoError(t, err)
+	_, err = loadSSHSigner(string(pem.EncodeToMemory(block)), nil)
+	assert.ErrorContains(t, err, "SSH key is encrypted")
+	_, err = loadSSHSigner(string(pem.EncodeToMemory(block)), []byte("wrong"))
+	assert.Error(t, err)
+	signer, err := loadSSHSigner(string(pem.EncodeToMemory(block)), []byte("secret"))
+	assert.NoError(t, err)
+	assert.NotNil(t, signer)
+}
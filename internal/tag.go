@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// CreateTag creates an annotated tag for the detected release commit with the release notes as message,
+// the tag is signed if signer is set. It returns nil if no release commit was detected,
+// an existing tag is kept if it points to the release commit.
+func (repository *Repository) CreateTag(repo *git.Repository, tagger *object.Signature, signer git.Signer) (*plumbing.Reference, error) {
+	if repository.unreleased == "" {
+		return nil, nil
+	}
+	target := plumbing.NewHash(repository.unreleased)
+
+	if existing, err := repo.Tag(repository.Latest); err == nil {
+		hash := existing.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			hash = tag.Target
+		}
+		if hash != target {
+			return nil, fmt.Errorf("tag %s already exists at %s instead of %s", repository.Latest, hash, target)
+		}
+		log.Printf("[semanticore] tag %s already exists", repository.Latest)
+		return existing, nil
+	} else if !errors.Is(err, git.ErrTagNotFound) {
+		return nil, fmt.Errorf("unable to read tag %s: %w", repository.Latest, err)
+	}
+
+	message := repository.unreleasedChangelog
+	if message == "" {
+		message = "Release " + repository.Latest
+	}
+	ref, err := createTag(repo, repository.Latest, target, tagger, message, signer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create tag %s: %w", repository.Latest, err)
+	}
+	log.Printf("[semanticore] created tag %s at %s", repository.Latest, target)
+	return ref, nil
+}
+
+// createTag creates an annotated tag of a commit, go-git only supports OpenPGP keys to sign tags
+func createTag(repo *git.Repository, name string, target plumbing.Hash, tagger *object.Signature, message string, signer git.Signer) (*plumbing.Reference, error) {
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     *tagger,
+		Message:    message,
+		TargetType: plumbing.CommitObject,
+		Target:     target,
+	}
+	if !strings.HasSuffix(tag.Message, "\n") {
+		tag.Message += "\n"
+	}
+
+	if signer != nil {
+		encoded := &plumbing.MemoryObject{}
+		if err := tag.EncodeWithoutSignature(encoded); err != nil {
+			return nil, err
+		}
+		reader, err := encoded.Reader()
+		if err != nil {
+			return nil, err
+		}
+		signature, err := signer.Sign(reader)
+		if err != nil {
+			return nil, err
+		}
+		tag.PGPSignature = string(signature)
+	}
+
+	obj := repo.Storer.NewEncodedObject()
+	if err := tag.Encode(obj); err != nil {
+		return nil, err
+	}
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	ref := plumbing.NewHashReference(plumbing.NewTagReferenceName(name), hash)
+	if err := repo.Storer.SetReference(ref); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+// ReleaseTag creates the release of a tag which was already pushed, see CreateTag
+func (repository *Repository) ReleaseTag(backend Backend) error {
+	if err := backend.Release(repository.Latest, "", repository.unreleasedChangelog); err != nil {
+		return fmt.Errorf("unable to release existing tag %s: %w", repository.Latest, err)
+	}
+	return nil
+}
+
+// ReleaseMissingTag creates the release of the latest tag if it has none, e.g. because a previous run pushed the tag
+// but failed to create its release. The release notes are the tag message, or the changelog of the tagged commit.
+// Nothing is done while a release commit is pending, see CreateTag.
+func (repository *Repository) ReleaseMissingTag(repo *git.Repository, backend Backend) error {
+	if repository.unreleased != "" || repository.tagged == nil {
+		return nil
+	}
+	name := repository.tagged.Name().Short()
+	exists, err := backend.HasRelease(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	hash := repository.tagged.Hash()
+	var changelog string
+	if tag, err := repo.TagObject(hash); err == nil {
+		hash = tag.Target
+		changelog = strings.TrimSpace(tag.Message)
+	}
+	if changelog == "" {
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return fmt.Errorf("unable to read tag %s: %w", name, err)
+		}
+		changelog = releaseChangelog(commit)
+	}
+
+	log.Printf("[semanticore] tag %s has no release, creating it", name)
+	if err := backend.Release(name, "", changelog); err != nil {
+		return fmt.Errorf("unable to release existing tag %s: %w", name, err)
+	}
+	return nil
+}
+
+// PushTag pushes the tag to the origin remote
+func PushTag(repo *git.Repository, tag *plumbing.Reference, auth transport.AuthMethod) error {
+	refSpec := config.RefSpec(fmt.Sprintf("%s:%s", tag.Name(), tag.Name()))
+	err := repo.Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       auth,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to push tag %s: %w", tag.Name().Short(), err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// UnverifiedCommit is a commit of the release without a valid signature of a trusted key
+type UnverifiedCommit struct {
+	Hash    string
+	Subject string
+	Author  string
+	// Reason is either `unsigned` or describes why the signature is not trusted
+	Reason string
+}
+
+// verifyCommits checks the PGP signatures of the commits against the trusted keyring,
+// keys are checked at the signature creation time with signatureTime and at now otherwise
+func verifyCommits(commits []*object.Commit, trusted openpgp.EntityList, signatureTime bool) []UnverifiedCommit {
+	var unverified []UnverifiedCommit
+	for _, commit := range commits {
+		reason := verifyCommit(commit, trusted, signatureTime)
+		if reason == "" {
+			continue
+		}
+		unverified = append(unverified, UnverifiedCommit{
+			Hash:    commit.Hash.String(),
+			Subject: subject(commit.Message),
+			Author:  commit.Author.Email,
+			Reason:  reason,
+		})
+	}
+	return unverified
+}
+
+// verifyCommit returns why the commit is not verified, it is empty for valid signatures of trusted keys
+func verifyCommit(commit *object.Commit, trusted openpgp.EntityList, signatureTime bool) string {
+	if commit.PGPSignature == "" {
+		return "unsigned"
+	}
+	if !strings.HasPrefix(commit.PGPSignature, "-----BEGIN PGP SIGNATURE-----") {
+		return "no PGP signature"
+	}
+	encoded := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(encoded); err != nil {
+		return fmt.Sprintf("unable to encode commit: %s", err)
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return fmt.Sprintf("unable to encode commit: %s", err)
+	}
+	config := &packet.Config{}
+	if signatureTime {
+		created, err := signatureCreationTime(commit.PGPSignature)
+		if err != nil {
+			return fmt.Sprintf("invalid signature: %s", err)
+		}
+		// a signature from the future is checked at now
+		if now := time.Now(); created.After(now) {
+			created = now
+		}
+		config.Time = func() time.Time { return created }
+	}
+	if _, err := openpgp.CheckArmoredDetachedSignature(trusted, reader, strings.NewReader(commit.PGPSignature), config); err != nil {
+		return fmt.Sprintf("untrusted signature: %s", err)
+	}
+	return ""
+}
+
+// signatureCreationTime returns the creation time of an armored signature
+func signatureCreationTime(armored string) (time.Time, error) {
+	block, err := armor.Decode(strings.NewReader(armored))
+	if err != nil {
+		return time.Time{}, err
+	}
+	p, err := packet.Read(block.Body)
+	if err != nil {
+		return time.Time{}, err
+	}
+	signature, ok := p.(*packet.Signature)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected packet %T", p)
+	}
+	return signature.CreationTime, nil
+}
+
+// checkSignatures verifies the commits of the release if trusted keys are configured
+func checkSignatures(repository *Repository, commits []*object.Commit, options Options) error {
+	if options.TrustedKeys == "" {
+		return nil
+	}
+	trusted, err := loadGPGKeyRing(options.TrustedKeys)
+	if err != nil {
+		return fmt.Errorf("unable to read trusted keys: %w", err)
+	}
+
+	repository.Unverified = verifyCommits(commits, trusted, options.SignatureTime)
+	if len(repository.Unverified) == 0 {
+		return nil
+	}
+	summary := FormatUnverifiedCommits(repository.Unverified)
+	if options.RequireSignatures {
+		return fmt.Errorf("%d commits are not signed by a trusted key:\n%s", len(repository.Unverified), summary)
+	}
+	log.Printf("[semanticore] %d commits are not signed by a trusted key:\n%s", len(repository.Unverified), summary)
+	return nil
+}
+
+// FormatUnverifiedCommits renders the unverified commits as markdown list
+func FormatUnverifiedCommits(unverified []UnverifiedCommit) string {
+	var b strings.Builder
+	for _, commit := range unverified {
+		fmt.Fprintf(&b, "- `%s` %s by %s: %s\n", commit.Hash[:8], commit.Subject, commit.Author, commit.Reason)
+	}
+	return b.String()
+}
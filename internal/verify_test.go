@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRepositorySignatures(t *testing.T) {
+	trusted := newTestGPGKey(t, "trusted", nil)
+	untrusted := newTestGPGKey(t, "untrusted", nil)
+	var keyring bytes.Buffer
+	w, err := armor.Encode(&keyring, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, trusted.Serialize(w))
+	assert.NoError(t, w.Close())
+
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+	file, err := mockWt.Filesystem.Create("signed.file")
+	assert.NoError(t, err)
+	signedCommit := func(msg string, entity *openpgp.Entity) string {
+		file.Write([]byte(msg))
+		mockWt.Add("signed.file")
+		hash, err := mockWt.Commit(msg, &git.CommitOptions{
+			Author: &object.Signature{Name: "testing", Email: "testing@example.com", When: time.Now()},
+			Signer: openpgpSigner{entity: entity},
+		})
+		assert.NoError(t, err)
+		return hash.String()
+	}
+
+	released := testCommit("feat: released unsigned feature")
+	mockRepo.CreateTag("v1.0.0", released, nil)
+	signedCommit("feat: trusted feature", trusted)
+	unsigned := testCommit("fix: unsigned fix").String()
+	foreign := signedCommit("fix: untrusted fix", untrusted)
+
+	repository, err := ReadRepository(mockRepo, Options{})
+	assert.NoError(t, err)
+	assert.Empty(t, repository.Unverified)
+
+	options := Options{TrustedKeys: keyring.String()}
+	repository, err = ReadRepository(mockRepo, options)
+	assert.NoError(t, err)
+	assert.Len(t, repository.Unverified, 2)
+	assert.Equal(t, UnverifiedCommit{Hash: foreign, Subject: "fix: untrusted fix", Author: "testing@example.com", Reason: "untrusted signature: openpgp: signature made by unknown entity"}, repository.Unverified[0])
+	assert.Equal(t, UnverifiedCommit{Hash: unsigned, Subject: "fix: unsigned fix", Author: "testing@example.com", Reason: "unsigned"}, repository.Unverified[1])
+	assert.Equal(t, "- `"+foreign[:8]+"` fix: untrusted fix by testing@example.com: untrusted signature: openpgp: signature made by unknown entity\n- `"+unsigned[:8]+"` fix: unsigned fix by testing@example.com: unsigned\n", FormatUnverifiedCommits(repository.Unverified))
+
+	options.RequireSignatures = true
+	_, err = ReadRepository(mockRepo, options)
+	assert.ErrorContains(t, err, "2 commits are not signed by a trusted key")
+
+	_, err = ReadRepository(mockRepo, Options{TrustedKeys: "no keyring"})
+	assert.ErrorContains(t, err, "unable to read trusted keys")
+}
+
+// pastSigner signs commits with a GPG key at a fixed time
+type pastSigner struct {
+	entity *openpgp.Entity
+	when   time.Time
+}
+
+func (s pastSigner) Sign(message io.Reader) ([]byte, error) {
+	var b bytes.Buffer
+	err := openpgp.ArmoredDetachSign(&b, s.entity, message, &packet.Config{Time: func() time.Time { return s.when }})
+	return b.Bytes(), err
+}
+
+func TestReadRepositorySignaturesHistory(t *testing.T) {
+	past := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	trusted := newTestGPGKey(t, "trusted", nil)
+	expired := newTestGPGKey(t, "expired", &packet.Config{Time: func() time.Time { return past }, KeyLifetimeSecs: 3600})
+	var keyring bytes.Buffer
+	w, err := armor.Encode(&keyring, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, trusted.Serialize(w))
+	assert.NoError(t, expired.Serialize(w))
+	assert.NoError(t, w.Close())
+
+	mockRepo, mockWt, testCommit := newTestRepository(t)
+	commit := func(msg string, signer git.Signer, when time.Time, parents ...plumbing.Hash) plumbing.Hash {
+		hash, err := mockWt.Commit(msg, &git.CommitOptions{
+			Author:            &object.Signature{Name: "testing", Email: "testing@example.com", When: when},
+			Parents:           parents,
+			Signer:            signer,
+			AllowEmptyCommits: true,
+		})
+		assert.NoError(t, err)
+		return hash
+	}
+
+	released := testCommit("feat: released unsigned feature")
+	mockRepo.CreateTag("v1.0.0", released, nil)
+	old := commit("fix: signed before the key expired", pastSigner{entity: expired, when: past.Add(time.Minute)}, past.Add(time.Minute), released)
+	side := commit("wip: unsigned branch commit", nil, time.Now(), old)
+	merge := commit("Merge pull request #12 from x/y\n\nfeat: merged feature", openpgpSigner{entity: trusted}, time.Now(), old, side)
+	commit("Release v1.1.0", openpgpSigner{entity: trusted}, time.Now(), merge)
+
+	// the release commit is not tagged yet, its commits are verified although there are no updates
+	options := Options{CommitSource: CommitSourceFirstParent, TrustedKeys: keyring.String()}
+	repository, err := ReadRepository(mockRepo, options)
+	assert.NoError(t, err)
+	reasons := make(map[string]string)
+	for _, commit := range repository.Unverified {
+		reasons[commit.Hash] = commit.Reason
+	}
+	assert.Equal(t, map[string]string{side.String(): "unsigned", old.String(): "untrusted signature: openpgp: key expired"}, reasons)
+
+	options.RequireSignatures = true
+	_, err = ReadRepository(mockRepo, options)
+	assert.ErrorContains(t, err, "2 commits are not signed by a trusted key")
+
+	// keys are checked at the signature creation time, the expired key was valid back then
+	options.SignatureTime = true
+	_, err = ReadRepository(mockRepo, options)
+	assert.ErrorContains(t, err, "1 commits are not signed by a trusted key")
+	options.RequireSignatures = false
+	repository, err = ReadRepository(mockRepo, options)
+	assert.NoError(t, err)
+	if assert.Len(t, repository.Unverified, 1) {
+		assert.Equal(t, UnverifiedCommit{Hash: side.String(), Subject: "wip: unsigned branch commit", Author: "testing@example.com", Reason: "unsigned"}, repository.Unverified[0])
+	}
+
+	// the committer date does not matter, only the time of the signature
+	commit("fix: committed after the key expired", pastSigner{entity: expired, when: past.Add(time.Minute)}, time.Now())
+	repository, err = ReadRepository(mockRepo, options)
+	assert.NoError(t, err)
+	assert.Len(t, repository.Unverified, 1)
+	options.SignatureTime = false
+	repository, err = ReadRepository(mockRepo, options)
+	assert.NoError(t, err)
+	assert.Len(t, repository.Unverified, 3)
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var versionRegex = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)$`)
+
+// ParseVersion parses a version like `v1.2.3` or `1.2.3`
+func ParseVersion(version string) (vPrefix string, major, minor, patch int, err error) {
+	match := versionRegex.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return "", 0, 0, 0, fmt.Errorf("invalid version %q, expected format is [v]X.Y.Z", version)
+	}
+	major, _ = strconv.Atoi(match[2])
+	minor, _ = strconv.Atoi(match[3])
+	patch, _ = strconv.Atoi(match[4])
+	return match[1], major, minor, patch, nil
+}
+
+// compareVersions returns -1, 0 or 1 if version a is lower, equal or greater than version b
+func compareVersions(aMajor, aMinor, aPatch, bMajor, bMinor, bPatch int) int {
+	for _, d := range []int{aMajor - bMajor, aMinor - bMinor, aPatch - bPatch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+var releaseAsRegex = regexp.MustCompile(`(?im)^Release-As:\s*(\S+)\s*$`)
+
+// DetectReleaseAs returns the version forced by a `Release-As: X.Y.Z` footer, if any
+func DetectReleaseAs(msg string) string {
+	match := releaseAsRegex.FindStringSubmatch(msg)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	var cases = []struct {
+		version             string
+		vPrefix             string
+		major, minor, patch int
+		valid               bool
+	}{
+		{"v1.2.3", "v", 1, 2, 3, true},
+		{"1.2.3", "", 1, 2, 3, true},
+		{" 10.20.30 ", "", 10, 20, 30, true},
+		{"1.2", "", 0, 0, 0, false},
+		{"1.2.3-rc.1", "", 0, 0, 0, false},
+		{"release-1.2.3", "", 0, 0, 0, false},
+	}
+
+	for _, c := range cases {
+		vPrefix, major, minor, patch, err := ParseVersion(c.version)
+		if c.valid {
+			assert.NoError(t, err, c.version)
+		} else {
+			assert.Error(t, err, c.version)
+		}
+		assert.Equal(t, c.vPrefix, vPrefix, c.version)
+		assert.Equal(t, []int{c.major, c.minor, c.patch}, []int{major, minor, patch}, c.version)
+	}
+}
+
+func TestDetectReleaseAs(t *testing.T) {
+	assert.Equal(t, "3.0.0", DetectReleaseAs("feat: marketing\n\nRelease-As: 3.0.0"))
+	assert.Equal(t, "v3.0.0", DetectReleaseAs("feat: marketing\n\nrelease-as: v3.0.0\nRefs: #12"))
+	assert.Equal(t, "", DetectReleaseAs("feat: marketing\n\nWe should Release-As: 3.0.0 soon"))
+	assert.Equal(t, "", DetectReleaseAs("feat: marketing"))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/aoepeople/semanticore/internal"
+)
+
+var (
+	scopes               = flag.String("scopes", "", "comma separated list of known commit scopes, used by lint and -unknown-scopes")
+	scopeAliases         = flag.String("scope-aliases", "", "comma separated scope aliases, e.g. \"dependencies=deps,frontend=ui\"")
+	requireScope         = flag.Bool("require-scope", false, "lint: reject commits without scope")
+	maxDescriptionLength = flag.Int("max-description-length", 100, "lint: maximum length of the commit description, 0 disables the check")
+	lintTarget           = flag.String("lint-target", "", "lint: branch the merge request targets, defaults to the backend's main branch or \"main\"")
+	lintComment          = flag.Bool("lint-comment", false, "lint: post the result as comment on the merge request, requires SEMANTICORE_TOKEN")
+	messageFile          = flag.String("message-file", "", "lint: check the commit message in the given file instead of the branch, used by the commit-msg hook")
+	hookCommand          = flag.String("hook-command", "semanticore", "hook install: command the commit-msg hook calls")
+	hookForce            = flag.Bool("hook-force", false, "hook install: replace an existing commit-msg hook")
+)
+
+// hookFlags are not passed on to the commit-msg hook
+var hookFlags = map[string]bool{"hook-command": true, "hook-force": true, "message-file": true}
+
+func lintRules() internal.LintRules {
+	return internal.LintRules{
+		Scopes:                splitList(*scopes),
+		ScopeAliases:          parseAliases(*scopeAliases),
+		RequireScope:          *requireScope,
+		Gitmoji:               *gitmojiCommits,
+		ReleaseCommitTemplate: *releaseCommitMessage,
+		MaxDescriptionLength:  *maxDescriptionLength,
+	}
+}
+
+// lint checks the commits of the current branch which are not part of the target branch and exits non-zero on problems
+func lint(repo *git.Repository, backend internal.Backend) {
+	target := *lintTarget
+	if target == "" && backend != nil {
+		mainBranch, err := backend.MainBranch()
+		try(err)
+		target = mainBranch
+	}
+	if target == "" {
+		target = "main"
+	}
+
+	targetHash, err := repo.ResolveRevision(plumbing.Revision("refs/remotes/origin/" + target))
+	if err != nil {
+		targetHash, err = repo.ResolveRevision(plumbing.Revision(target))
+	}
+	if err != nil {
+		try(fmt.Errorf("unable to resolve lint target %q: %w", target, err))
+	}
+
+	results, err := internal.LintCommits(repo, *targetHash, lintRules())
+	try(err)
+
+	report, failed := internal.FormatLintResults(results)
+	fmt.Print(report)
+
+	if *lintComment {
+		if number := currentMergeRequest(); backend != nil && number > 0 {
+			try(backend.CommentMergeRequest(number, "## Semanticore commit lint\n\n"+report))
+		} else {
+			log.Println("[semanticore] no backend or merge request detected, the lint result is not commented")
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// lintMessageFile checks a single commit message and exits non-zero on problems
+func lintMessageFile(path string) {
+	msg, err := internal.ReadCommitMessageFile(path)
+	try(err)
+	if msg == "" {
+		return
+	}
+
+	problems := internal.LintMessage(msg, lintRules())
+	if len(problems) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "semanticore: commit message does not follow the conventions:\n")
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+	}
+	os.Exit(1)
+}
+
+// installHook writes the commit-msg hook, passing on all flags set for this call so the hook uses the same configuration
+func installHook(repo *git.Repository) {
+	var args []string
+	flag.Visit(func(f *flag.Flag) {
+		if !hookFlags[f.Name] {
+			args = append(args, fmt.Sprintf("-%s=%s", f.Name, f.Value.String()))
+		}
+	})
+
+	path, err := internal.InstallCommitMsgHook(repo, *hookCommand, args, *hookForce)
+	try(err)
+	log.Printf("[semanticore] installed commit-msg hook at %s", path)
+}
+
+var githubPullRefRegexp = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// currentMergeRequest detects the merge request of the pipeline from Gitlab or Github environment variables
+func currentMergeRequest() int {
+	if iid, err := strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID")); err == nil {
+		return iid
+	}
+	if match := githubPullRefRegexp.FindStringSubmatch(os.Getenv("GITHUB_REF")); match != nil {
+		number, _ := strconv.Atoi(match[1])
+		return number
+	}
+	return 0
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func parseAliases(s string) map[string]string {
+	aliases := make(map[string]string)
+	for _, alias := range splitList(s) {
+		from, to, ok := strings.Cut(alias, "=")
+		if !ok {
+			try(fmt.Errorf("invalid scope alias %q, expected format is alias=scope", alias))
+		}
+		aliases[strings.TrimSpace(from)] = strings.TrimSpace(to)
+	}
+	return aliases
+}
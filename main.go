@@ -15,6 +15,7 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 
 	"github.com/aoepeople/semanticore/internal"
 	"github.com/aoepeople/semanticore/internal/hook"
@@ -27,22 +28,72 @@ func try(err error) {
 }
 
 var (
-	useBackend         = flag.String("backend", os.Getenv("SEMANTICORE_BACKEND"), "configure backend use either \"github\" or \"gitlab\" - we'll try to autodetect if empty")
-	createMajor        = flag.Bool("major", false, "release major versions")
-	createRelease      = flag.Bool("release", true, "create release alongside tags")
-	createMergeRequest = flag.Bool("merge-request", true, "create merge release for branch")
-	authorName         = flag.String("git-author-name", emptyFallback(os.Getenv("GIT_AUTHOR_NAME"), "Semanticore Bot"), "author name for the git commits, falls back to env var GIT_AUTHOR_NAME and afterwards to \"Semanticore Bot\"")
-	authorEmail        = flag.String("git-author-email", emptyFallback(os.Getenv("GIT_AUTHOR_EMAIL"), "semanticore@aoe.com"), "author email for the git commits, falls back to env var GIT_AUTHOR_EMAIL and afterwards to \"semanticore@aoe.com\"")
-	committerName      = flag.String("git-committer-name", emptyFallback(os.Getenv("GIT_COMMITTER_NAME"), "Semanticore Bot"), "committer name for the git commits, falls back to env var GIT_COMMITTER_NAME and afterwards to \"Semanticore Bot\"")
-	committerEmail     = flag.String("git-committer-email", emptyFallback(os.Getenv("GIT_COMMITTER_EMAIL"), "semanticore@aoe.com"), "committer email for the git commits, falls back to env var GIT_COMMITTER_EMAIL and afterwards to \"semanticore@aoe.com\"")
-	changelogMaxLines  = flag.Int("changelog-max-lines", 0, "trim the changelog to the last version including the maximum configured lines")
-	changelogFileName  = flag.String("changelog-file-name", emptyFallback(os.Getenv("CHANGELOG_FILE_NAME"), "Changelog.md"), "filename for changelog, falls back to env var CHANGELOG_FILE_NAME and afterwards to \"Changelog.md\"")
-	signKeyFilePath    = flag.String("sign-key-file", emptyFallback(os.Getenv("SEMANTICORE_SIGN_KEY_FILE"), ""), "path to GPG private key file for signing commits")
+	useBackend            = flag.String("backend", os.Getenv("SEMANTICORE_BACKEND"), "configure backend use either \"github\" or \"gitlab\" - we'll try to autodetect if empty")
+	createMajor           = flag.Bool("major", false, "release major versions")
+	semverZero            = flag.Bool("semver-zero", false, "while the major version is 0, breaking changes bump the minor version and features the patch version")
+	semverZeroMinor       = flag.Bool("semver-zero-feature-minor", false, "with -semver-zero, keep bumping the minor version for features")
+	stable                = flag.Bool("stable", false, "graduate a 0.x version to 1.0.0 with the next release")
+	tagPrefix             = flag.String("tag-prefix", "", "prefix of version tags, e.g. \"app-\" for tags like \"app-v1.2.3\"")
+	issueURL              = flag.String("issue-url", "", "URL template for issue references like \"#123\", \"{id}\" is replaced by the issue number, defaults to the backend's issues")
+	trackerURL            = flag.String("issue-tracker-url", "", "URL template for issue references of an external tracker like \"PROJ-42\", e.g. \"https://jira.example.com/browse/{id}\"")
+	commitURL             = flag.String("commit-url", "", "URL template for commit hashes, \"{hash}\" is replaced by the commit hash, defaults to the backend's commit page")
+	compareURL            = flag.String("compare-url", "", "URL template for the diff of a version, \"{from}\" and \"{to}\" are replaced by the tags, defaults to the backend's compare page")
+	splitSquash           = flag.Bool("split-squash", false, "create a changelog entry for every conventional commit listed in the body of squash commits")
+	commitSource          = flag.String("commit-source", string(internal.CommitSourceAll), "commits used for the changelog: \"all\" non-merge commits, \"first-parent\" history with pull request titles of merge commits, or \"squash\" for the first-parent history without merges")
+	mergeRequestLinks     = flag.Bool("merge-request-links", false, "add the pull/merge request and its author to every changelog entry, requires SEMANTICORE_TOKEN")
+	excludePaths          = flag.String("exclude-paths", "", "comma separated path globs, commits only changing matching paths are omitted from the changelog, e.g. \"docs/**,.github/**\"")
+	unknownScopes         = flag.String("unknown-scopes", "", "handling of scopes not listed in -scopes: \"warn\" or \"fail\", ignored if empty")
+	groupByScope          = flag.Bool("group-by-scope", false, "group the entries of every changelog section by scope with sub-headings")
+	gitmojiCommits        = flag.Bool("gitmoji", false, "parse commits starting with a gitmoji like \"✨ add export\" or \":bug: fix crash\"")
+	contributors          = flag.Bool("contributors", false, "add a section listing the authors and co-authors of the release to the changelog, \".mailmap\" is applied and bots are omitted")
+	excludeAuthors        = flag.String("exclude-contributors", "", "comma separated names or emails omitted from the contributors in addition to the semanticore author and committer")
+	contributorHandles    = flag.Bool("contributor-handles", false, "look up the Github or Gitlab usernames of the contributors, requires SEMANTICORE_TOKEN")
+	trustedKeys           = flag.String("trusted-keys", os.Getenv("SEMANTICORE_TRUSTED_KEYS_FILE"), "path to an armored PGP keyring, the commits of a release are verified against it, falls back to env var SEMANTICORE_TRUSTED_KEYS_FILE")
+	requireSignatures     = flag.Bool("require-signatures", false, "with -trusted-keys, fail if a commit of the release is not signed by a trusted key")
+	signatureTime         = flag.Bool("signature-time", false, "with -trusted-keys, check the keys at the creation time of each signature instead of now, expired keys can sign backdated commits")
+	forceVersion          = flag.String("version", "", "force the next version instead of computing it from the commits, e.g. \"3.0.0\"")
+	createRelease         = flag.Bool("release", true, "create release alongside tags")
+	signedTag             = flag.Bool("signed-tag", false, "create and push a signed annotated tag before creating the release, requires a sign key")
+	localTag              = flag.Bool("local-tag", false, "without SEMANTICORE_TOKEN, create the release tag locally and push it with the configured git credentials")
+	createMergeRequest    = flag.Bool("merge-request", true, "create merge release for branch")
+	authorName            = flag.String("git-author-name", emptyFallback(os.Getenv("GIT_AUTHOR_NAME"), "Semanticore Bot"), "author name for the git commits, falls back to env var GIT_AUTHOR_NAME and afterwards to \"Semanticore Bot\"")
+	authorEmail           = flag.String("git-author-email", emptyFallback(os.Getenv("GIT_AUTHOR_EMAIL"), "semanticore@aoe.com"), "author email for the git commits, falls back to env var GIT_AUTHOR_EMAIL and afterwards to \"semanticore@aoe.com\"")
+	committerName         = flag.String("git-committer-name", emptyFallback(os.Getenv("GIT_COMMITTER_NAME"), "Semanticore Bot"), "committer name for the git commits, falls back to env var GIT_COMMITTER_NAME and afterwards to \"Semanticore Bot\"")
+	committerEmail        = flag.String("git-committer-email", emptyFallback(os.Getenv("GIT_COMMITTER_EMAIL"), "semanticore@aoe.com"), "committer email for the git commits, falls back to env var GIT_COMMITTER_EMAIL and afterwards to \"semanticore@aoe.com\"")
+	changelogMaxLines     = flag.Int("changelog-max-lines", 0, "trim the changelog to the last version including the maximum configured lines")
+	changelogFileName     = flag.String("changelog-file-name", emptyFallback(os.Getenv("CHANGELOG_FILE_NAME"), "Changelog.md"), "filename for changelog, falls back to env var CHANGELOG_FILE_NAME and afterwards to \"Changelog.md\"")
+	releaseBranch         = flag.String("release-branch", emptyFallback(os.Getenv("SEMANTICORE_RELEASE_BRANCH"), internal.DefaultReleaseBranch), "branch the release commit is pushed to and the merge request is opened from, falls back to env var SEMANTICORE_RELEASE_BRANCH")
+	releaseCommitMessage  = flag.String("release-commit-message", emptyFallback(os.Getenv("SEMANTICORE_RELEASE_COMMIT_MESSAGE"), internal.DefaultReleaseCommitTemplate), "template of the release commit message and merge request title, {version} is replaced with the version, falls back to env var SEMANTICORE_RELEASE_COMMIT_MESSAGE")
+	preserveReleaseEdits  = flag.Bool("preserve-release-edits", false, "do not update the release branch if it contains commits not authored by semanticore, e.g. manual changelog edits, and comment on the merge request instead")
+	deepen                = flag.Bool("deepen", true, "fetch more history from origin if a shallow clone does not reach the latest version, otherwise fail")
+	signKeyID             = flag.String("sign-key-id", os.Getenv("SEMANTICORE_SIGN_KEY_ID"), "key ID or fingerprint of the GPG key or subkey used for signing, required if the key file contains several keys, falls back to env var SEMANTICORE_SIGN_KEY_ID")
+	signKeyPassphraseFile = flag.String("sign-key-passphrase-file", os.Getenv("SEMANTICORE_SIGN_KEY_PASSPHRASE_FILE"), "path to a file containing the passphrase of an encrypted sign key, alternatively set env var SEMANTICORE_SIGN_KEY_PASSPHRASE")
+	signKeyFilePath       = flag.String("sign-key-file", emptyFallback(os.Getenv("SEMANTICORE_SIGN_KEY_FILE"), ""), "path to the GPG or OpenSSH private key file for signing commits and tags")
 )
 
 func main() {
 	flag.Parse()
 
+	command := ""
+	if flag.NArg() > 0 && (flag.Arg(0) == "lint" || flag.Arg(0) == "hook") {
+		command = flag.Arg(0)
+		args := flag.Args()[1:]
+		if command == "hook" {
+			if len(args) == 0 || args[0] != "install" {
+				try(errors.New("unknown hook command, use \"semanticore hook install\""))
+			}
+			args = args[1:]
+		}
+		try(flag.CommandLine.Parse(args))
+	}
+
+	try(internal.CheckReleaseCommitTemplate(*releaseCommitMessage))
+
+	if command == "lint" && *messageFile != "" {
+		lintMessageFile(*messageFile)
+		return
+	}
+
 	dir := "."
 	if flag.NArg() > 0 {
 		dir = flag.Arg(0)
@@ -52,6 +103,11 @@ func main() {
 	repo, err := git.PlainOpen(".")
 	try(err)
 
+	if command == "hook" {
+		installHook(repo)
+		return
+	}
+
 	remote, err := repo.Remote("origin")
 	try(err)
 	remoteUrl, err := url.Parse(remote.Config().URLs[0])
@@ -63,19 +119,104 @@ func main() {
 	if os.Getenv("SEMANTICORE_TOKEN") == "" {
 		log.Println("[semanticore] SEMANTICORE_TOKEN unset, no merge requests will be handled")
 	} else if *useBackend == "github" || remoteUrl.Host == "github.com" {
-		backend = internal.NewGithubBackend(os.Getenv("SEMANTICORE_TOKEN"), repoId)
+		backend = internal.NewGithubBackend(os.Getenv("SEMANTICORE_TOKEN"), repoId, *releaseBranch)
 	} else if *useBackend == "gitlab" || strings.Contains(remoteUrl.Host, "gitlab") {
-		backend = internal.NewGitlabBackend(os.Getenv("SEMANTICORE_TOKEN"), remoteUrl.Host, repoId)
+		backend = internal.NewGitlabBackend(os.Getenv("SEMANTICORE_TOKEN"), remoteUrl.Host, repoId, *releaseBranch)
+	}
+
+	if command == "lint" {
+		lint(repo, backend)
+		return
+	}
+
+	switch internal.CommitSource(*commitSource) {
+	case internal.CommitSourceAll, internal.CommitSourceFirstParent, internal.CommitSourceSquash:
+	default:
+		try(fmt.Errorf("unknown commit source %q", *commitSource))
+	}
+	if *unknownScopes != "" && *unknownScopes != "warn" && *unknownScopes != "fail" {
+		try(fmt.Errorf("unknown -unknown-scopes value %q, use \"warn\" or \"fail\"", *unknownScopes))
 	}
 
 	head, err := repo.Head()
 	try(err)
 
-	repository, err := internal.ReadRepository(repo, *createMajor)
+	links := internal.Links{
+		Issue:   *issueURL,
+		Tracker: *trackerURL,
+		Commit:  *commitURL,
+		Compare: *compareURL,
+	}
+	if backend != nil {
+		links = links.Merge(backend.Links())
+	}
+
+	var trustedKeyring []byte
+	if *trustedKeys != "" {
+		trustedKeyring, err = os.ReadFile(*trustedKeys)
+		try(err)
+	}
+
+	options := internal.Options{
+		CreateMajor:            *createMajor,
+		SemverZero:             *semverZero,
+		SemverZeroFeatureMinor: *semverZeroMinor,
+		Stable:                 *stable,
+		Version:                *forceVersion,
+		TagPrefix:              *tagPrefix,
+		Links:                  links,
+		SplitSquash:            *splitSquash,
+		CommitSource:           internal.CommitSource(*commitSource),
+		Backend:                backend,
+		MergeRequestLinks:      *mergeRequestLinks,
+		ExcludePaths:           strings.Split(*excludePaths, ","),
+		Scopes:                 splitList(*scopes),
+		UnknownScopes:          *unknownScopes,
+		ScopeAliases:           parseAliases(*scopeAliases),
+		GroupByScope:           *groupByScope,
+		Gitmoji:                *gitmojiCommits,
+		Contributors:           *contributors,
+		ExcludeContributors:    append(strings.Split(*excludeAuthors, ","), *authorEmail, *committerEmail),
+		ContributorHandles:     *contributorHandles,
+		TrustedKeys:            string(trustedKeyring),
+		RequireSignatures:      *requireSignatures,
+		SignatureTime:          *signatureTime,
+		ReleaseCommitTemplate:  *releaseCommitMessage,
+	}
+	if *deepen {
+		var auth transport.AuthMethod = backend
+		if backend == nil {
+			auth = internal.GitCredentials(remote.Config().URLs[0])
+		}
+		try(internal.DeepenHistory(repo, options, auth))
+	}
+	repository, err := internal.ReadRepository(repo, options)
 	try(err)
 
-	if backend != nil && *createRelease {
-		repository.Release(backend)
+	signer, err := internal.TryCreateSigner(internal.SignKeyOptions{
+		KeyFile:        *signKeyFilePath,
+		KeyID:          *signKeyID,
+		PassphraseFile: *signKeyPassphraseFile,
+	})
+	if errors.Is(err, internal.ErrNoSigningKeyFound) {
+		log.Printf("[semanticore] no signing key found, commits and tags will not be signed")
+	} else if err != nil {
+		try(err)
+	}
+
+	if backend != nil && *createRelease && *signedTag {
+		if signer == nil {
+			try(errors.New("-signed-tag requires a sign key, see -sign-key-file"))
+		}
+		if pushTag(repo, repository, signer, backend) {
+			logReleaseError(repository.ReleaseTag(backend))
+		}
+		logReleaseError(repository.ReleaseMissingTag(repo, backend))
+	} else if backend != nil && *createRelease {
+		logReleaseError(repository.Release(backend))
+		logReleaseError(repository.ReleaseMissingTag(repo, backend))
+	} else if backend == nil && *localTag {
+		pushTag(repo, repository, signer, internal.GitCredentials(remote.Config().URLs[0]))
 	}
 
 	changelog := repository.Changelog()
@@ -90,6 +231,14 @@ func main() {
 	if !*createMergeRequest {
 		return
 	}
+	if backend != nil && *preserveReleaseEdits {
+		version := fmt.Sprintf("%s%d.%d.%d", repository.VPrefix, repository.Major, repository.Minor, repository.Patch)
+		edited, err := internal.ReleaseBranchEdited(repo, backend, *releaseBranch, head.Hash(), *authorEmail, version)
+		try(err)
+		if edited {
+			return
+		}
+	}
 
 	wt, err := repo.Worktree()
 	try(err)
@@ -125,13 +274,6 @@ func main() {
 
 	hook.NpmUpdateVersionHook(wt, repository)
 
-	signKey, err := internal.TryCreateSignKey(signKeyFilePath)
-	if errors.Is(err, internal.ErrNoSigningKeyFound) {
-		log.Printf("[semanticore] no signing key found, commit will not be signed")
-	} else if err != nil {
-		try(err)
-	}
-
 	commitOptions := &git.CommitOptions{
 		Author: &object.Signature{
 			Name:  *authorName,
@@ -143,10 +285,11 @@ func main() {
 			Email: *committerEmail,
 			When:  time.Now(),
 		},
-		SignKey: signKey,
+		Signer: signer,
 	}
 
-	commit, err := wt.Commit(fmt.Sprintf("Release %s%d.%d.%d", repository.VPrefix, repository.Major, repository.Minor, repository.Patch), commitOptions)
+	releaseMessage := internal.ReleaseCommitMessage(*releaseCommitMessage, fmt.Sprintf("%s%d.%d.%d", repository.VPrefix, repository.Major, repository.Minor, repository.Patch))
+	commit, err := wt.Commit(releaseMessage, commitOptions)
 	try(err)
 
 	log.Printf("[semanticore] committed changelog: %s", commit.String())
@@ -162,27 +305,35 @@ func main() {
 	}
 	try(repo.Push(&git.PushOptions{
 		RemoteName: "origin",
-		RefSpecs:   []config.RefSpec{config.RefSpec(commit.String() + ":refs/heads/semanticore/release")},
+		RefSpecs:   []config.RefSpec{config.RefSpec(commit.String() + ":refs/heads/" + *releaseBranch)},
 		Force:      true,
 		Auth:       backend,
 		Progress:   os.Stdout,
 	}))
 
 	releasetype := "patch 🩹"
-	if repository.Breaking && *createMajor {
+	if repository.Bump == internal.BumpMajor {
 		releasetype = "major 👏"
-	} else if len(repository.Features) > 0 {
+	} else if repository.Bump == internal.BumpMinor {
 		releasetype = "minor 📦"
 	}
 	labels := "Release 🏆," + releasetype
+	override := ""
+	if repository.OverriddenBy != "" {
+		override = fmt.Sprintf(" The version was overridden by %s.", repository.OverriddenBy)
+	}
+	unverified := ""
+	if len(repository.Unverified) > 0 {
+		unverified = fmt.Sprintf("\n## ⚠️ Unverified commits\n\nThe following commits are not signed by a trusted key:\n\n%s", internal.FormatUnverifiedCommits(repository.Unverified))
+	}
 	description := fmt.Sprintf(`# Release %s%d.%d.%d 🏆
 
 ## Summary
 
 There are %s commits since %s.
 
-This is a %s release.
-
+This is a %s release.%s
+%s
 Merge this pull request to commit the changelog and have Semanticore create a new release on the next pipeline run.
 
 %s
@@ -190,12 +341,35 @@ Merge this pull request to commit the changelog and have Semanticore create a ne
 ---
 
 This changelog was generated by your friendly [Semanticore Release Bot](https://github.com/aoepeople/semanticore)
-`, repository.VPrefix, repository.Major, repository.Minor, repository.Patch, strings.Join(repository.Details, ", "), repository.Latest, releasetype, strings.TrimSpace(changelog))
+`, repository.VPrefix, repository.Major, repository.Minor, repository.Patch, strings.Join(repository.Details, ", "), repository.Latest, releasetype, override, unverified, strings.TrimSpace(changelog))
 
 	mainBranch, err := backend.MainBranch()
 	try(err)
 
-	try(backend.MergeRequest(string(mainBranch), fmt.Sprintf("Release %s%d.%d.%d", repository.VPrefix, repository.Major, repository.Minor, repository.Patch), description, labels))
+	try(backend.MergeRequest(string(mainBranch), releaseMessage, description, labels))
+}
+
+// logReleaseError logs a failed release without aborting, the release of a pushed tag is retried by the next run
+func logReleaseError(err error) {
+	if err != nil {
+		log.Printf("[semanticore] %s", err)
+	}
+}
+
+// pushTag creates and pushes the tag of a detected release commit, it returns false if there is none
+func pushTag(repo *git.Repository, repository *internal.Repository, signer git.Signer, auth transport.AuthMethod) bool {
+	tag, err := repository.CreateTag(repo, &object.Signature{
+		Name:  *committerName,
+		Email: *committerEmail,
+		When:  time.Now(),
+	}, signer)
+	try(err)
+	if tag == nil {
+		return false
+	}
+	try(internal.PushTag(repo, tag, auth))
+	log.Printf("[semanticore] pushed tag %s", tag.Name().Short())
+	return true
 }
 
 func emptyFallback(s, fallback string) string {